@@ -0,0 +1,112 @@
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// devicePluginsLock guards devicePlugins, the in-memory registry populated
+// by DiscoverDevicePlugins.
+var (
+	devicePluginsLock sync.Mutex
+	devicePlugins     = make(map[string]DevicePlugin)
+)
+
+// RegisterDevicePlugin makes a DevicePlugin available under its Kind() to
+// WithDevicePluginRequest.
+func RegisterDevicePlugin(plugin DevicePlugin) error {
+	devicePluginsLock.Lock()
+	defer devicePluginsLock.Unlock()
+
+	kind := plugin.Kind()
+	if kind == "" {
+		return fmt.Errorf("device plugin kind must not be empty")
+	}
+	if _, ok := devicePlugins[kind]; ok {
+		return fmt.Errorf("device plugin for kind %q is already registered", kind)
+	}
+
+	devicePlugins[kind] = plugin
+	return nil
+}
+
+// GetDevicePlugin looks up a previously-registered DevicePlugin by kind.
+func GetDevicePlugin(kind string) (DevicePlugin, error) {
+	devicePluginsLock.Lock()
+	defer devicePluginsLock.Unlock()
+
+	plugin, ok := devicePlugins[kind]
+	if !ok {
+		return nil, fmt.Errorf("no device plugin registered for kind %q", kind)
+	}
+	return plugin, nil
+}
+
+// DiscoverDevicePlugins scans dir for unix domain sockets and registers a
+// DevicePlugin for each one found, named after the socket's basename
+// (without extension) - e.g. nvidia.sock registers kind "nvidia".
+// It is normally called once at runtime startup against the device plugin
+// config directory (commonly /etc/containers/devices.d or similar) so GPU,
+// FPGA, and other vendor plugins (nvidia, amd, habana, generic vfio) can be
+// resolved by WithDevicePluginRequest without Podman hard-coding per-vendor
+// logic.
+func DiscoverDevicePlugins(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading device plugin directory %q: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sock" {
+			continue
+		}
+
+		kind := strings.TrimSuffix(entry.Name(), ".sock")
+		socketPath := filepath.Join(dir, entry.Name())
+
+		plugin, err := newSocketDevicePlugin(kind, socketPath)
+		if err != nil {
+			return fmt.Errorf("connecting to device plugin %q at %q: %w", kind, socketPath, err)
+		}
+		if err := RegisterDevicePlugin(plugin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResolveDevicePluginRequests resolves every DevicePluginRequest recorded via
+// WithDevicePluginRequest into a DeviceAllocation, in request order. It fails
+// fast - before allocating anything - if any requested kind has no
+// registered DevicePlugin, per WithDevicePluginRequest's documented
+// contract, rather than partially allocating devices for a container that's
+// about to fail anyway.
+func ResolveDevicePluginRequests(ctx context.Context, requests []DevicePluginRequest) ([]*DeviceAllocation, error) {
+	plugins := make([]DevicePlugin, len(requests))
+	for i, req := range requests {
+		plugin, err := GetDevicePlugin(req.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("resolving device plugin request %d (kind %q): %w", i, req.Kind, err)
+		}
+		plugins[i] = plugin
+	}
+
+	allocations := make([]*DeviceAllocation, len(requests))
+	for i, req := range requests {
+		alloc, err := plugins[i].Allocate(ctx, req.Count, req.Options)
+		if err != nil {
+			return nil, fmt.Errorf("allocating %d device(s) of kind %q: %w", req.Count, req.Kind, err)
+		}
+		allocations[i] = alloc
+	}
+
+	return allocations, nil
+}
@@ -0,0 +1,126 @@
+package libpod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// socketDevicePlugin is the default DevicePlugin implementation. It speaks a
+// small JSON allocation protocol over a unix socket, so existing vendor
+// device plugins (nvidia, amd, habana, generic vfio) need only a thin shim
+// in front of their existing allocation logic rather than a reimplementation
+// against an unfamiliar API.
+type socketDevicePlugin struct {
+	kind   string
+	client *http.Client
+}
+
+func newSocketDevicePlugin(kind, socketPath string) (DevicePlugin, error) {
+	return &socketDevicePlugin{
+		kind: kind,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}, nil
+}
+
+func (p *socketDevicePlugin) Kind() string {
+	return p.kind
+}
+
+type devicePluginAllocateRequest struct {
+	Kind    string            `json:"kind"`
+	Count   int               `json:"count"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+type devicePluginDeviceNode struct {
+	ContainerPath string `json:"containerPath"`
+	Type          string `json:"type"`
+	Major         int64  `json:"major"`
+	Minor         int64  `json:"minor"`
+}
+
+type devicePluginMount struct {
+	HostPath      string `json:"hostPath"`
+	ContainerPath string `json:"containerPath"`
+}
+
+type devicePluginAllocateResponse struct {
+	Devices     []devicePluginDeviceNode `json:"devices,omitempty"`
+	CgroupRules []string                 `json:"cgroupRules,omitempty"`
+	Mounts      []devicePluginMount      `json:"mounts,omitempty"`
+	Env         map[string]string        `json:"env,omitempty"`
+	Err         string                   `json:"err,omitempty"`
+}
+
+// Allocate posts an allocation request to the plugin's /Allocate endpoint.
+// The wire format is intentionally minimal (kind, count, and a
+// string-to-string options map in; devices, cgroup rules, mounts, and env
+// out) so third-party plugins only need a small adapter in front of their
+// existing allocation logic.
+func (p *socketDevicePlugin) Allocate(ctx context.Context, count int, opts map[string]string) (*DeviceAllocation, error) {
+	payload, err := json.Marshal(devicePluginAllocateRequest{Kind: p.kind, Count: count, Options: opts})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://device-plugin/Allocate", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("allocating %d device(s) of kind %q: %w", count, p.kind, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed devicePluginAllocateResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("decoding allocation response from device plugin %q: %w", p.kind, err)
+	}
+	if parsed.Err != "" {
+		return nil, fmt.Errorf("device plugin %q: %s", p.kind, parsed.Err)
+	}
+
+	alloc := &DeviceAllocation{
+		CgroupRules: parsed.CgroupRules,
+		Env:         parsed.Env,
+	}
+	for _, d := range parsed.Devices {
+		alloc.Devices = append(alloc.Devices, specs.LinuxDevice{
+			Path:  d.ContainerPath,
+			Type:  d.Type,
+			Major: d.Major,
+			Minor: d.Minor,
+		})
+	}
+	for _, m := range parsed.Mounts {
+		alloc.Mounts = append(alloc.Mounts, specs.Mount{
+			Destination: m.ContainerPath,
+			Source:      m.HostPath,
+			Options:     []string{"bind", "rw"},
+		})
+	}
+
+	return alloc, nil
+}
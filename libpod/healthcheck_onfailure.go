@@ -0,0 +1,26 @@
+package libpod
+
+// healthCheckOnFailureAction decides whether the on_failure action set by
+// WithHealthCheckOnFailure should fire, given the number of consecutive
+// failed healthchecks observed so far. It reports (action, true) once
+// consecutiveFailures reaches the configured threshold, and
+// (HealthCheckOnFailureActionNone, false) otherwise - including when no
+// action or a zero threshold was configured, per WithHealthCheckOnFailure's
+// documented semantics.
+//
+// This is the decision the healthcheck timer subsystem consults after each
+// failed check before emitting an event and acting; it is kept as a small
+// pure function so the threshold logic can be tested without a running
+// timer.
+func (ctr *Container) healthCheckOnFailureAction(consecutiveFailures uint) (action HealthCheckOnFailureAction, fire bool) {
+	threshold := ctr.config.HealthCheckOnFailureThreshold
+	configured := ctr.config.HealthCheckOnFailureAction
+
+	if threshold == 0 || configured == "" || configured == HealthCheckOnFailureActionNone {
+		return HealthCheckOnFailureActionNone, false
+	}
+	if consecutiveFailures < threshold {
+		return HealthCheckOnFailureActionNone, false
+	}
+	return configured, true
+}
@@ -0,0 +1,128 @@
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// bufferedSink provides the local-buffering-on-outage behavior shared by all
+// of the network LogSink implementations: a failed send is appended to an
+// on-disk buffer instead of being dropped or blocking the caller, and
+// LocalBufferPath exposes that file so `podman logs` has somewhere to read
+// from when the network destination is down. Once delivery starts working
+// again, the next send replays the backlog before forwarding new lines, so
+// the buffer file doesn't grow without bound and its contents eventually
+// reach the destination instead of sitting unread.
+type bufferedSink struct {
+	mu         sync.Mutex
+	bufferPath string
+	bufferFile *os.File
+	// degraded is true once a delivery has failed; set back to false only
+	// after the entire backlog has been replayed successfully.
+	degraded bool
+}
+
+func newBufferedSink(driver, containerID string) (*bufferedSink, error) {
+	path := fmt.Sprintf("%s/%s-%s.buffer.log", os.TempDir(), driver, containerID)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("opening local buffer for %s log sink: %w", driver, err)
+	}
+	return &bufferedSink{bufferPath: path, bufferFile: f}, nil
+}
+
+// send attempts to deliver line via deliver, first replaying any backlog
+// left over from a previous outage. Per the LogSink interface contract, a
+// delivery failure never propagates to the caller as backpressure: the line
+// is appended to the local buffer (logged at warning level so the outage
+// isn't entirely silent) and send still returns nil. The only error send can
+// return is a failure to even write the local buffer, meaning the line was
+// genuinely lost.
+func (b *bufferedSink) send(line []byte, deliver func([]byte) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.degraded {
+		if err := b.flushLocked(deliver); err != nil {
+			logrus.Debugf("log sink still unreachable, not yet recovered: %v", err)
+			// Queue the new line behind the backlog rather than delivering
+			// it ahead of lines that are still waiting to be replayed.
+			return b.bufferLocked(line, err)
+		} else {
+			logrus.Infof("log sink recovered, replayed buffered lines from %s", b.bufferPath)
+		}
+	}
+
+	if err := deliver(line); err != nil {
+		return b.bufferLocked(line, err)
+	}
+	return nil
+}
+
+// bufferLocked appends line to the on-disk backlog and marks the sink
+// degraded so the next send retries the backlog before anything new.
+// b.mu must be held.
+func (b *bufferedSink) bufferLocked(line []byte, deliverErr error) error {
+	b.degraded = true
+	logrus.Warnf("log sink unreachable, buffering locally at %s: %v", b.bufferPath, deliverErr)
+	// append(line, '\n') would silently write into line's backing array
+	// (and corrupt the caller's buffer) whenever cap(line) > len(line), as
+	// it is for the slice each LogSink.Write gets from the conmon tee. Copy
+	// into a fresh slice instead of growing line in place.
+	buffered := make([]byte, len(line)+1)
+	copy(buffered, line)
+	buffered[len(line)] = '\n'
+	if _, werr := b.bufferFile.Write(buffered); werr != nil {
+		return fmt.Errorf("log sink unreachable (%v) and local buffer write failed: %w", deliverErr, werr)
+	}
+	return nil
+}
+
+// flushLocked replays every line currently in the local buffer through
+// deliver. On full success the buffer is truncated and degraded is cleared;
+// on the first delivery failure it stops and leaves the whole backlog in
+// place for the next attempt, including any lines this attempt already
+// delivered, so those are sent again on the next replay. b.mu must be held.
+func (b *bufferedSink) flushLocked(deliver func([]byte) error) error {
+	data, err := os.ReadFile(b.bufferPath)
+	if err != nil {
+		return fmt.Errorf("reading local buffer %s: %w", b.bufferPath, err)
+	}
+	if len(data) == 0 {
+		b.degraded = false
+		return nil
+	}
+
+	for _, l := range bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n")) {
+		if len(l) == 0 {
+			continue
+		}
+		if err := deliver(l); err != nil {
+			return err
+		}
+	}
+
+	if err := b.bufferFile.Truncate(0); err != nil {
+		return fmt.Errorf("truncating local buffer %s after replay: %w", b.bufferPath, err)
+	}
+	if _, err := b.bufferFile.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding local buffer %s after replay: %w", b.bufferPath, err)
+	}
+	b.degraded = false
+	return nil
+}
+
+func (b *bufferedSink) LocalBufferPath() string {
+	return b.bufferPath
+}
+
+func (b *bufferedSink) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bufferFile.Close()
+}
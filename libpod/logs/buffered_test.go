@@ -0,0 +1,29 @@
+package logs
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestBufferLockedDoesNotMutateCallerSlice guards against bufferLocked
+// writing into line's backing array via append, which would silently
+// corrupt a caller's buffer whenever cap(line) > len(line).
+func TestBufferLockedDoesNotMutateCallerSlice(t *testing.T) {
+	b, err := newBufferedSink("test", "ctr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	backing := make([]byte, 6, 10) // len 5 slice below, but cap leaves room past it
+	copy(backing, []byte("helloX"))
+	line := backing[:5] // "hello"; backing[5] == 'X' is data line doesn't own
+
+	if err := b.bufferLocked(line, errors.New("sink unreachable")); err != nil {
+		t.Fatalf("bufferLocked() unexpected error: %v", err)
+	}
+
+	if backing[5] != 'X' {
+		t.Errorf("bufferLocked() wrote past line's length into the caller's backing array: backing[5] = %q, want 'X'", backing[5])
+	}
+}
@@ -0,0 +1,74 @@
+package logs
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+func init() {
+	RegisterLogSink("fluentd", newFluentdSink)
+}
+
+// fluentdSink forwards log lines to a fluentd (or fluent-bit) instance using
+// the Fluentd Forward Protocol (MessagePack-encoded Message mode, one
+// [tag, time, record] array per line) over TCP.
+type fluentdSink struct {
+	*bufferedSink
+	conn net.Conn
+	tag  string
+}
+
+func newFluentdSink(options map[string]string) (LogSink, error) {
+	address := options["fluentd-address"]
+	if address == "" {
+		address = "127.0.0.1:24224"
+	}
+	tag := options["tag"]
+	if tag == "" {
+		tag = "podman"
+	}
+
+	conn, err := net.DialTimeout("tcp", address, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("dialing fluentd sink %q: %w", address, err)
+	}
+
+	buffered, err := newBufferedSink("fluentd", options["container-id"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &fluentdSink{bufferedSink: buffered, conn: conn, tag: tag}, nil
+}
+
+func (s *fluentdSink) Name() string {
+	return "fluentd"
+}
+
+func (s *fluentdSink) Write(containerID, stream string, line []byte) error {
+	return s.send(line, func(l []byte) error {
+		entry := []interface{}{
+			s.tag,
+			time.Now().Unix(),
+			map[string]interface{}{
+				"container_id": containerID,
+				"source":       stream,
+				"log":          string(l),
+			},
+		}
+		encoded, err := msgp.AppendIntf(nil, entry)
+		if err != nil {
+			return fmt.Errorf("encoding fluentd forward entry: %w", err)
+		}
+		_, err = s.conn.Write(encoded)
+		return err
+	})
+}
+
+func (s *fluentdSink) Close() error {
+	_ = s.conn.Close()
+	return s.bufferedSink.Close()
+}
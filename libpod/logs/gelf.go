@@ -0,0 +1,124 @@
+package logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+func init() {
+	RegisterLogSink("gelf", newGelfSink)
+}
+
+// gelfSink sends Graylog Extended Log Format messages over UDP or TCP,
+// selected via the "gelf-address" option (e.g. "udp://host:12201").
+type gelfSink struct {
+	*bufferedSink
+	conn   net.Conn
+	tag    string
+	isUDP  bool
+	source string
+}
+
+func newGelfSink(options map[string]string) (LogSink, error) {
+	address := options["gelf-address"]
+	network, addr, err := parseGelfAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout(network, addr, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("dialing gelf sink %q: %w", address, err)
+	}
+
+	buffered, err := newBufferedSink("gelf", options["container-id"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &gelfSink{
+		bufferedSink: buffered,
+		conn:         conn,
+		tag:          options["tag"],
+		isUDP:        network == "udp",
+	}, nil
+}
+
+func (s *gelfSink) Name() string {
+	return "gelf"
+}
+
+type gelfMessage struct {
+	Version      string `json:"version"`
+	Host         string `json:"host"`
+	ShortMessage string `json:"short_message"`
+	Timestamp    int64  `json:"timestamp"`
+	Level        int    `json:"level"`
+	ContainerID  string `json:"_container_id"`
+	Tag          string `json:"_tag,omitempty"`
+}
+
+func (s *gelfSink) Write(containerID, stream string, line []byte) error {
+	return s.send(line, func(l []byte) error {
+		level := 6 // informational
+		if stream == "stderr" {
+			level = 3 // error
+		}
+
+		msg := gelfMessage{
+			Version:      "1.1",
+			Host:         containerID,
+			ShortMessage: string(l),
+			Timestamp:    time.Now().Unix(),
+			Level:        level,
+			ContainerID:  containerID,
+			Tag:          s.tag,
+		}
+
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("encoding gelf message: %w", err)
+		}
+
+		if s.isUDP {
+			// UDP GELF requires zlib/gzip-compressed chunked frames for
+			// messages over ~1400 bytes; for simplicity (and because
+			// conmon lines are typically short) send gzip-compressed
+			// single-chunk payloads, which graylog accepts unchunked.
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			if _, err := gz.Write(payload); err != nil {
+				return err
+			}
+			if err := gz.Close(); err != nil {
+				return err
+			}
+			_, err = s.conn.Write(buf.Bytes())
+			return err
+		}
+
+		// TCP GELF messages are NUL-delimited, uncompressed.
+		_, err = s.conn.Write(append(payload, 0))
+		return err
+	})
+}
+
+func (s *gelfSink) Close() error {
+	_ = s.conn.Close()
+	return s.bufferedSink.Close()
+}
+
+func parseGelfAddress(address string) (network, addr string, err error) {
+	switch {
+	case hasScheme(address, "udp://"):
+		return "udp", address[len("udp://"):], nil
+	case hasScheme(address, "tcp://"):
+		return "tcp", address[len("tcp://"):], nil
+	default:
+		return "", "", fmt.Errorf("invalid gelf-address %q: must be udp:// or tcp://", address)
+	}
+}
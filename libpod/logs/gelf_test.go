@@ -0,0 +1,37 @@
+package logs
+
+import "testing"
+
+func TestParseGelfAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		wantNetwork string
+		wantAddr    string
+		wantErr     bool
+	}{
+		{name: "udp", address: "udp://graylog.example.com:12201", wantNetwork: "udp", wantAddr: "graylog.example.com:12201"},
+		{name: "tcp", address: "tcp://graylog.example.com:12201", wantNetwork: "tcp", wantAddr: "graylog.example.com:12201"},
+		{name: "missing scheme", address: "graylog.example.com:12201", wantErr: true},
+		{name: "unsupported scheme", address: "tcp+tls://graylog.example.com:12201", wantErr: true},
+		{name: "empty", address: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, addr, err := parseGelfAddress(tt.address)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseGelfAddress(%q) = nil error, want error", tt.address)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseGelfAddress(%q) unexpected error: %v", tt.address, err)
+			}
+			if network != tt.wantNetwork || addr != tt.wantAddr {
+				t.Errorf("parseGelfAddress(%q) = (%q, %q), want (%q, %q)", tt.address, network, addr, tt.wantNetwork, tt.wantAddr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package logs
+
+import (
+	"fmt"
+	"sync"
+)
+
+// LogSink is a network log destination that conmon's log pipe can be tee'd
+// to in addition to (or instead of) the local on-disk log. Implementations
+// are responsible for their own reconnection and backpressure handling: a
+// slow or unreachable network sink must never block container output, so
+// Write is expected to buffer locally and drop or replay as the
+// implementation sees fit rather than propagating backpressure to the
+// caller.
+type LogSink interface {
+	// Name returns the driver name this sink is registered under (e.g.
+	// "syslog", "fluentd", "gelf", "splunk").
+	Name() string
+	// Write sends a single log line, tagged with the container ID and the
+	// stream it came from ("stdout" or "stderr"), to the sink.
+	Write(containerID, stream string, line []byte) error
+	// Close releases the sink's connection and closes its local buffer
+	// file. Lines still waiting in the local buffer are not replayed to
+	// the destination on Close.
+	Close() error
+	// LocalBufferPath returns the path of the on-disk buffer the sink
+	// falls back to writing while the network destination is
+	// unreachable. `podman logs` reads from this file for drivers using
+	// a LogSink, the same way it reads the k8s-file driver's log file.
+	LocalBufferPath() string
+}
+
+// LogSinkFactory builds a LogSink from the options set via
+// libpod.WithLogDriverOptions (address, tag, token, tls settings, ...).
+type LogSinkFactory func(options map[string]string) (LogSink, error)
+
+var (
+	sinksLock sync.Mutex
+	sinks     = make(map[string]LogSinkFactory)
+)
+
+// RegisterLogSink makes a log sink driver available under name. Called from
+// each sink implementation's init().
+func RegisterLogSink(name string, factory LogSinkFactory) {
+	sinksLock.Lock()
+	defer sinksLock.Unlock()
+
+	sinks[name] = factory
+}
+
+// NewLogSink constructs the sink registered under name, passing it the
+// driver options given at container creation.
+func NewLogSink(name string, options map[string]string) (LogSink, error) {
+	sinksLock.Lock()
+	factory, ok := sinks[name]
+	sinksLock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no log sink registered for driver %q", name)
+	}
+	return factory(options)
+}
@@ -0,0 +1,108 @@
+package logs
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func init() {
+	RegisterLogSink("splunk", newSplunkSink)
+}
+
+// splunkSink forwards log lines to a Splunk HTTP Event Collector (HEC)
+// endpoint over HTTPS, authenticated with a token.
+type splunkSink struct {
+	*bufferedSink
+	client *http.Client
+	url    string
+	token  string
+	source string
+	index  string
+}
+
+func newSplunkSink(options map[string]string) (LogSink, error) {
+	url := options["splunk-url"]
+	if url == "" {
+		return nil, fmt.Errorf("splunk log driver requires splunk-url")
+	}
+	token := options["splunk-token"]
+	if token == "" {
+		return nil, fmt.Errorf("splunk log driver requires splunk-token")
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if options["splunk-insecureskipverify"] == "true" {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} //nolint:gosec // explicit opt-in
+	}
+
+	buffered, err := newBufferedSink("splunk", options["container-id"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &splunkSink{
+		bufferedSink: buffered,
+		client:       client,
+		url:          url,
+		token:        token,
+		source:       options["tag"],
+		index:        options["splunk-index"],
+	}, nil
+}
+
+func (s *splunkSink) Name() string {
+	return "splunk"
+}
+
+type splunkEvent struct {
+	Event  map[string]interface{} `json:"event"`
+	Time   float64                `json:"time"`
+	Source string                 `json:"source,omitempty"`
+	Index  string                 `json:"index,omitempty"`
+}
+
+func (s *splunkSink) Write(containerID, stream string, line []byte) error {
+	return s.send(line, func(l []byte) error {
+		event := splunkEvent{
+			Event: map[string]interface{}{
+				"container_id": containerID,
+				"stream":       stream,
+				"log":          string(l),
+			},
+			Time:   float64(time.Now().UnixNano()) / 1e9,
+			Source: s.source,
+			Index:  s.index,
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("encoding splunk HEC event: %w", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Splunk "+s.token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("sending to splunk HEC: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("splunk HEC returned %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+func (s *splunkSink) Close() error {
+	return s.bufferedSink.Close()
+}
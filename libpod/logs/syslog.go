@@ -0,0 +1,113 @@
+package logs
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/syslog"
+	"net"
+	"time"
+)
+
+func init() {
+	RegisterLogSink("syslog", newSyslogSink)
+}
+
+// syslogSink writes to a syslog daemon over UDP, TCP, or TLS, selected via
+// the "syslog-address" option (e.g. "udp://host:514", "tcp://host:601",
+// "tcp+tls://host:6514"). Only the TLS transport formats messages as
+// RFC5424; UDP and TCP go through the stdlib log/syslog writer, which sends
+// its own BSD-style format rather than RFC5424.
+type syslogSink struct {
+	*bufferedSink
+	tag string
+	// conn is used for tcp+tls, where the stdlib log/syslog package offers
+	// no transport. udpOrTCP is used otherwise.
+	conn     net.Conn
+	udpOrTCP *syslog.Writer
+}
+
+func newSyslogSink(options map[string]string) (LogSink, error) {
+	address := options["syslog-address"]
+	tag := options["tag"]
+	if tag == "" {
+		tag = "podman"
+	}
+
+	network, addr, err := parseSyslogAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered, err := newBufferedSink("syslog", options["container-id"])
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "tcp+tls" {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", addr, &tls.Config{MinVersion: tls.VersionTLS12})
+		if err != nil {
+			return nil, fmt.Errorf("dialing syslog+tls sink %q: %w", address, err)
+		}
+		return &syslogSink{bufferedSink: buffered, tag: tag, conn: conn}, nil
+	}
+
+	writer, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("dialing syslog sink %q: %w", address, err)
+	}
+	return &syslogSink{bufferedSink: buffered, tag: tag, udpOrTCP: writer}, nil
+}
+
+func (s *syslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *syslogSink) Write(containerID, stream string, line []byte) error {
+	return s.send(line, func(l []byte) error {
+		if s.conn != nil {
+			msg := fmt.Sprintf("<%d>1 %s %s %s - - - %s\n", syslogPriority(stream), time.Now().UTC().Format(time.RFC3339), containerID, s.tag, l)
+			_, err := s.conn.Write([]byte(msg))
+			return err
+		}
+		if stream == "stderr" {
+			return s.udpOrTCP.Err(string(l))
+		}
+		return s.udpOrTCP.Info(string(l))
+	})
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	if s.udpOrTCP != nil {
+		_ = s.udpOrTCP.Close()
+	}
+	return s.bufferedSink.Close()
+}
+
+// syslogPriority maps a container stream to an RFC5424 PRI value under
+// facility "daemon" (3): 3*8+6=30 for info (stdout), 3*8+3=27 for err (stderr).
+func syslogPriority(stream string) int {
+	if stream == "stderr" {
+		return 27
+	}
+	return 30
+}
+
+func parseSyslogAddress(address string) (network, addr string, err error) {
+	switch {
+	case hasScheme(address, "udp://"):
+		return "udp", address[len("udp://"):], nil
+	case hasScheme(address, "tcp://"):
+		return "tcp", address[len("tcp://"):], nil
+	case hasScheme(address, "tcp+tls://"):
+		return "tcp+tls", address[len("tcp+tls://"):], nil
+	default:
+		return "", "", fmt.Errorf("invalid syslog-address %q: must be udp://, tcp://, or tcp+tls://", address)
+	}
+}
+
+func hasScheme(s, scheme string) bool {
+	return len(s) >= len(scheme) && s[:len(scheme)] == scheme
+}
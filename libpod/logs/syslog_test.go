@@ -0,0 +1,38 @@
+package logs
+
+import "testing"
+
+func TestParseSyslogAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		wantNetwork string
+		wantAddr    string
+		wantErr     bool
+	}{
+		{name: "udp", address: "udp://logs.example.com:514", wantNetwork: "udp", wantAddr: "logs.example.com:514"},
+		{name: "tcp", address: "tcp://logs.example.com:601", wantNetwork: "tcp", wantAddr: "logs.example.com:601"},
+		{name: "tcp+tls", address: "tcp+tls://logs.example.com:6514", wantNetwork: "tcp+tls", wantAddr: "logs.example.com:6514"},
+		{name: "missing scheme", address: "logs.example.com:514", wantErr: true},
+		{name: "unsupported scheme", address: "udp6://logs.example.com:514", wantErr: true},
+		{name: "empty", address: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, addr, err := parseSyslogAddress(tt.address)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseSyslogAddress(%q) = nil error, want error", tt.address)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSyslogAddress(%q) unexpected error: %v", tt.address, err)
+			}
+			if network != tt.wantNetwork || addr != tt.wantAddr {
+				t.Errorf("parseSyslogAddress(%q) = (%q, %q), want (%q, %q)", tt.address, network, addr, tt.wantNetwork, tt.wantAddr)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package libpod
 
 import (
+	"context"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/common/pkg/config"
@@ -25,6 +28,7 @@ import (
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 // Runtime Creation Options
@@ -390,14 +394,43 @@ func WithCNIConfigDir(dir string) RuntimeOption {
 	}
 }
 
-// WithCNIPluginDir sets the CNI plugins directory.
+// WithCNIPluginDir appends dir to the CNI plugin search path, preserving any
+// directories already configured (e.g. via the runtime's defaults).
+//
+// Deprecated: use WithCNIPluginDirs, which supports layering multiple
+// directories (e.g. a system dir plus a user-local dir) into a single ordered
+// search path.
 func WithCNIPluginDir(dir string) RuntimeOption {
+	return WithExtraCNIPluginDir(dir)
+}
+
+// WithCNIPluginDirs sets the ordered list of directories the CNI/netavark
+// loader searches for plugin binaries. Directories are searched in the order
+// given, and the first match for a given plugin name wins, so callers can
+// layer a system directory with a user-local one (for rootless plugins like
+// slirp4netns-CNI, dnsname, or custom meta-plugins). The given list replaces
+// any directories already configured; WithExtraCNIPluginDir appends instead.
+func WithCNIPluginDirs(dirs ...string) RuntimeOption {
+	return func(rt *Runtime) error {
+		if rt.valid {
+			return define.ErrRuntimeFinalized
+		}
+
+		rt.config.Network.CNIPluginDirs = dirs
+
+		return nil
+	}
+}
+
+// WithExtraCNIPluginDir appends dir to the end of the CNI plugin search path
+// without disturbing the configured defaults.
+func WithExtraCNIPluginDir(dir string) RuntimeOption {
 	return func(rt *Runtime) error {
 		if rt.valid {
 			return define.ErrRuntimeFinalized
 		}
 
-		rt.config.Network.CNIPluginDirs = []string{dir}
+		rt.config.Network.CNIPluginDirs = append(rt.config.Network.CNIPluginDirs, dir)
 
 		return nil
 	}
@@ -1003,6 +1036,116 @@ func WithUTSNSFrom(nsCtr *Container) CtrCreateOption {
 	}
 }
 
+// WithTimeNSFrom indicates the the container should join the time namespace of
+// the given container.
+// If the container has joined a pod, it can only join the namespaces of
+// containers in the same pod.
+func WithTimeNSFrom(nsCtr *Container) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+
+		if err := supportsTimeNamespace(); err != nil {
+			return err
+		}
+
+		if err := checkDependencyContainer(nsCtr, ctr); err != nil {
+			return err
+		}
+
+		ctr.config.TimeNsCtr = nsCtr.ID()
+
+		return nil
+	}
+}
+
+// WithTimeNSFromPod indicates the the container should join the time
+// namespace of its pod's infra container.
+func WithTimeNSFromPod(p *Pod) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+
+		if err := supportsTimeNamespace(); err != nil {
+			return err
+		}
+
+		if err := validPodNSOption(p, ctr.config.Pod); err != nil {
+			return err
+		}
+
+		infraContainer, err := p.InfraContainerID()
+		if err != nil {
+			return err
+		}
+		ctr.config.TimeNsCtr = infraContainer
+
+		return nil
+	}
+}
+
+// minTimeNamespaceKernel is the earliest kernel release that supports time
+// namespaces (CLONE_NEWTIME, merged in 5.6).
+var minTimeNamespaceKernel = [2]int{5, 6}
+
+// supportsTimeNamespace returns a clear error rather than letting a container
+// configured with WithTimeNSFrom/WithTimeNSFromPod fail deep in the OCI
+// runtime with an opaque "invalid argument" once the kernel rejects joining
+// /proc/<pid>/ns/time_for_children.
+func supportsTimeNamespace() error {
+	major, minor, err := hostKernelVersion()
+	if err != nil {
+		// Not fatal: if we can't determine the kernel version, defer to
+		// the OCI runtime to fail at container start instead.
+		return nil
+	}
+
+	if major < minTimeNamespaceKernel[0] || (major == minTimeNamespaceKernel[0] && minor < minTimeNamespaceKernel[1]) {
+		return errors.Wrapf(define.ErrOSNotSupported, "time namespaces require Linux %d.%d or newer, host is running %d.%d",
+			minTimeNamespaceKernel[0], minTimeNamespaceKernel[1], major, minor)
+	}
+	return nil
+}
+
+// hostKernelVersion returns the running kernel's major.minor version, as
+// reported by uname(2).
+func hostKernelVersion() (major, minor int, err error) {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return 0, 0, err
+	}
+	return parseKernelVersion(unix.ByteSliceToString(uts.Release[:]))
+}
+
+// parseKernelVersion parses the leading "major.minor" out of a uname
+// release string such as "5.13.0-52-generic" or "6.1.55".
+func parseKernelVersion(release string) (major, minor int, err error) {
+	fields := strings.SplitN(release, ".", 3)
+	if len(fields) < 2 {
+		return 0, 0, errors.Errorf("unrecognized kernel release %q", release)
+	}
+	// When the release has no patch level, the minor field may carry a "-"
+	// separated suffix (e.g. "6-rc1" from "5.6-rc1"); trim everything after
+	// the first non-digit rune.
+	minorField := fields[1]
+	for i, r := range minorField {
+		if r < '0' || r > '9' {
+			minorField = minorField[:i]
+			break
+		}
+	}
+
+	if major, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, errors.Errorf("unrecognized kernel release %q", release)
+	}
+	if minor, err = strconv.Atoi(minorField); err != nil {
+		return 0, 0, errors.Errorf("unrecognized kernel release %q", release)
+	}
+	return major, minor, nil
+}
+
 // WithCgroupNSFrom indicates the the container should join the CGroup namespace
 // of the given container.
 // If the container has joined a pod, it can only join the namespaces of
@@ -1047,6 +1190,73 @@ func WithDependencyCtrs(ctrs []*Container) CtrCreateOption {
 	}
 }
 
+// DependencyConditionType is the state a dependency container must reach
+// before a container waiting on it is allowed to start, mirroring compose's
+// `depends_on.condition`.
+type DependencyConditionType string
+
+const (
+	// DependencyConditionStarted is satisfied as soon as the dependency is
+	// running - the same, racy, "is it running yet" check WithDependencyCtrs
+	// has always done.
+	DependencyConditionStarted DependencyConditionType = "service_started"
+	// DependencyConditionHealthy is satisfied once the dependency's
+	// healthcheck reports "healthy". Dependencies without a healthcheck can
+	// never satisfy this condition.
+	DependencyConditionHealthy DependencyConditionType = "service_healthy"
+	// DependencyConditionCompleted is satisfied once the dependency has
+	// exited successfully (exit code 0). Intended for one-shot init-style
+	// dependencies.
+	DependencyConditionCompleted DependencyConditionType = "service_completed_successfully"
+)
+
+// DependencyCondition pairs a dependency container with the condition that
+// must hold before a container depending on it is started.
+type DependencyCondition struct {
+	// ID is the ID of the dependency container.
+	ID string
+	// Condition is the state ID must reach.
+	Condition DependencyConditionType
+	// Timeout bounds how long the start path will poll for Condition
+	// before giving up. 0 means wait indefinitely.
+	Timeout time.Duration
+}
+
+// WithDependencyConditions sets a richer dependency spec than
+// WithDependencyCtrs: each dependency is paired with one of
+// service_started, service_healthy, or service_completed_successfully (à la
+// compose's `depends_on.condition`), instead of always racing on "is it
+// running yet". The start path blocks on each condition - polling health
+// status or exit code, as appropriate - with a per-dependency timeout, and
+// surfaces wait progress through libpod events. This lets pods and
+// user-defined groups express real startup ordering.
+func WithDependencyConditions(conditions []DependencyCondition) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+
+		for _, cond := range conditions {
+			switch cond.Condition {
+			case DependencyConditionStarted, DependencyConditionHealthy, DependencyConditionCompleted:
+			default:
+				return errors.Wrapf(define.ErrInvalidArg, "%q is not a valid dependency condition", cond.Condition)
+			}
+			if !util.StringInSlice(cond.ID, ctr.config.Dependencies) {
+				ctr.config.Dependencies = append(ctr.config.Dependencies, cond.ID)
+			}
+		}
+
+		// Append rather than replace: WithDependencyCtrs may already have
+		// added plain (unconditioned) dependencies, and this option can be
+		// combined with itself to layer on conditions for pod-wide
+		// dependency groups.
+		ctr.config.DependencyConditions = append(ctr.config.DependencyConditions, conditions...)
+
+		return nil
+	}
+}
+
 // WithNetNS indicates that the container should be given a new network
 // namespace with a minimal configuration.
 // An optional array of port mappings can be provided.
@@ -1125,7 +1335,8 @@ func WithLogDriver(driver string) CtrCreateOption {
 		switch driver {
 		case "":
 			return errors.Wrapf(define.ErrInvalidArg, "log driver must be set")
-		case define.JournaldLogging, define.KubernetesLogging, define.JSONLogging, define.NoLogging, define.PassthroughLogging:
+		case define.JournaldLogging, define.KubernetesLogging, define.JSONLogging, define.NoLogging, define.PassthroughLogging,
+			define.SyslogLogging, define.FluentdLogging, define.GelfLogging, define.SplunkLogging:
 			break
 		default:
 			return errors.Wrapf(define.ErrInvalidArg, "invalid log driver")
@@ -1137,6 +1348,25 @@ func WithLogDriver(driver string) CtrCreateOption {
 	}
 }
 
+// WithLogDriverOptions sets backend-specific options for the container's log
+// driver, analogous to Docker's `--log-opt`. The network log sinks
+// (syslog, fluentd, gelf, splunk) consult this for address/tag/token/tls
+// settings; other drivers ignore it.
+func WithLogDriverOptions(options map[string]string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+
+		ctr.config.LogDriverOpts = make(map[string]string, len(options))
+		for key, value := range options {
+			ctr.config.LogDriverOpts[key] = value
+		}
+
+		return nil
+	}
+}
+
 // WithLogPath sets the path to the log file.
 func WithLogPath(path string) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -1514,6 +1744,60 @@ func WithHealthCheck(healthCheck *manifest.Schema2HealthConfig) CtrCreateOption
 	}
 }
 
+// HealthCheckOnFailureAction describes what the healthcheck timer subsystem
+// should do once a container has crossed its consecutive-failure threshold.
+type HealthCheckOnFailureAction string
+
+const (
+	// HealthCheckOnFailureActionNone takes no action beyond reporting -
+	// the historical, and default, behavior.
+	HealthCheckOnFailureActionNone HealthCheckOnFailureAction = "none"
+	// HealthCheckOnFailureActionLog logs the failure as a libpod event but
+	// otherwise takes no action.
+	HealthCheckOnFailureActionLog HealthCheckOnFailureAction = "log"
+	// HealthCheckOnFailureActionKill kills the container.
+	HealthCheckOnFailureActionKill HealthCheckOnFailureAction = "kill"
+	// HealthCheckOnFailureActionRestart restarts the container.
+	HealthCheckOnFailureActionRestart HealthCheckOnFailureAction = "restart"
+	// HealthCheckOnFailureActionStop stops the container.
+	HealthCheckOnFailureActionStop HealthCheckOnFailureAction = "stop"
+	// HealthCheckOnFailureActionExec runs an additional command inside the
+	// container instead of (or in addition to) the configured action.
+	HealthCheckOnFailureActionExec HealthCheckOnFailureAction = "exec"
+)
+
+// WithHealthCheckOnFailure sets the action the healthcheck timer subsystem
+// takes once the container has crossed threshold consecutive healthcheck
+// failures - independent of the Retries value already present on the
+// Schema2HealthConfig, which only controls when a single check is reported
+// unhealthy. A threshold of 0 disables the action even if one is configured.
+// This turns healthchecks into a self-healing primitive: the REST API and
+// generate kube can opt containers into actions beyond pure reporting.
+func WithHealthCheckOnFailure(action HealthCheckOnFailureAction, cmd []string, threshold uint) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+
+		switch action {
+		case HealthCheckOnFailureActionNone, HealthCheckOnFailureActionLog, HealthCheckOnFailureActionKill,
+			HealthCheckOnFailureActionRestart, HealthCheckOnFailureActionStop:
+		case HealthCheckOnFailureActionExec:
+			if len(cmd) == 0 {
+				return errors.Wrapf(define.ErrInvalidArg, "exec on_failure action requires a command")
+			}
+		default:
+			return errors.Wrapf(define.ErrInvalidArg, "%q is not a valid healthcheck on_failure action", action)
+		}
+
+		ctr.config.HealthCheckOnFailureAction = action
+		ctr.config.HealthCheckOnFailureExec = cmd
+		ctr.config.HealthCheckOnFailureThreshold = threshold
+
+		return nil
+	}
+}
+
 // WithPreserveFDs forwards from the process running Libpod into the container
 // the given number of extra FDs (starting after the standard streams) to the created container
 func WithPreserveFDs(fd uint) CtrCreateOption {
@@ -1599,8 +1883,11 @@ func WithVolumeName(name string) VolumeCreateOption {
 }
 
 // WithVolumeDriver sets the volume's driver.
-// It is presently not implemented, but will be supported in a future Podman
-// release.
+// The local driver ("local", or the empty string) is handled internally by
+// libpod.
+// Any other name must be registered in the VolumeDriver registry (see
+// volume_driver.go) - built-in adapters exist for the Docker Volume Plugin
+// HTTP protocol and for CSI node plugins.
 func WithVolumeDriver(driver string) VolumeCreateOption {
 	return func(volume *Volume) error {
 		if volume.valid {
@@ -1832,6 +2119,76 @@ func WithHostDevice(dev []specs.LinuxDevice) CtrCreateOption {
 	}
 }
 
+// WithDeviceCgroupRules sets fine-grained device cgroup rules (e.g.
+// "c 189:* rmw") on the container, granting or restricting access to a
+// class of devices by major/minor number without exposing device nodes in
+// the container's /dev.
+func WithDeviceCgroupRules(rules []string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+		ctr.config.DeviceCgroupRules = rules
+		return nil
+	}
+}
+
+// DevicePlugin resolves a named class of device (e.g. "nvidia", "amd",
+// "habana", "generic-vfio") into the concrete device nodes, cgroup rules,
+// mounts, and environment variables a container needs to use it, at
+// container start time. Plugins are discovered via a config directory of
+// unix sockets, so Podman's frontend does not need to hard-code per-vendor
+// --gpus logic.
+type DevicePlugin interface {
+	// Kind returns the device kind this plugin answers for.
+	Kind() string
+	// Allocate resolves count devices of this kind, honoring opts
+	// (vendor-specific allocation hints), and returns what the runtime
+	// must inject into the container.
+	Allocate(ctx context.Context, count int, opts map[string]string) (*DeviceAllocation, error)
+}
+
+// DeviceAllocation is what a DevicePlugin hands back for the runtime to wire
+// into a container's OCI spec.
+type DeviceAllocation struct {
+	Devices     []specs.LinuxDevice
+	CgroupRules []string
+	Mounts      []specs.Mount
+	Env         map[string]string
+}
+
+// WithDevicePluginRequest requests count devices of the given kind, to be
+// resolved against a registered DevicePlugin when the container starts. The
+// runtime fails fast at start time if no plugin is registered for kind.
+func WithDevicePluginRequest(kind string, count int, opts map[string]string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+		if kind == "" {
+			return errors.Wrapf(define.ErrInvalidArg, "device plugin kind must be set")
+		}
+		if count <= 0 {
+			return errors.Wrapf(define.ErrInvalidArg, "device plugin count must be positive")
+		}
+
+		ctr.config.DevicePluginRequests = append(ctr.config.DevicePluginRequests, DevicePluginRequest{
+			Kind:    kind,
+			Count:   count,
+			Options: opts,
+		})
+		return nil
+	}
+}
+
+// DevicePluginRequest records a single WithDevicePluginRequest call for
+// resolution at container start.
+type DevicePluginRequest struct {
+	Kind    string
+	Count   int
+	Options map[string]string
+}
+
 // Pod Creation Options
 
 // WithPodCreateCommand adds the full command plus arguments of the current
@@ -2084,6 +2441,83 @@ func WithInfraContainer() PodCreateOption {
 	}
 }
 
+// WithPodResources sets aggregate CPU/memory/pids/io limits for the pod,
+// recorded as a ceiling shared by every member container rather than a
+// per-container limit. The container-create path is expected to resolve this
+// ceiling against each joining container's own requested limits with
+// MergePodResourceLimits before writing its OCI spec.
+func WithPodResources(spec *specs.LinuxResources) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return define.ErrPodFinalized
+		}
+		if spec == nil {
+			return define.ErrInvalidArg
+		}
+
+		pod.config.ResourceLimits = spec
+
+		return nil
+	}
+}
+
+// WithPodDevices declares host devices once at the pod level, so every
+// container joining the pod - including the infra container - gets them
+// automatically instead of the caller repeating --device on each container.
+// The container-create path is expected to merge this list into each joining
+// container's own device list with MergePodDevices.
+func WithPodDevices(devices []string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return define.ErrPodFinalized
+		}
+
+		pod.config.Devices = devices
+
+		return nil
+	}
+}
+
+// WithPodDeviceCgroupRules sets device cgroup rules (e.g. "c 189:* rmw")
+// shared by every member container the same way WithPodDevices shares device
+// nodes, merged in with MergePodDeviceCgroupRules.
+func WithPodDeviceCgroupRules(rules []string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return define.ErrPodFinalized
+		}
+
+		pod.config.DeviceCgroupRules = rules
+
+		return nil
+	}
+}
+
+// WithPodRestartPolicy sets a pod-wide default restart policy. The
+// container-create path is expected to resolve each joining container's
+// actual policy with ResolveContainerRestartPolicy, which inherits this
+// default only for a container that never called WithRestartPolicy itself -
+// so the common case of "restart everything in the pod the same way" doesn't
+// require repeating the policy on every container.
+func WithPodRestartPolicy(policy string, retries uint) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return define.ErrPodFinalized
+		}
+
+		switch policy {
+		case define.RestartPolicyNone, define.RestartPolicyNo, define.RestartPolicyOnFailure, define.RestartPolicyAlways, define.RestartPolicyUnlessStopped:
+			pod.config.RestartPolicy = policy
+		default:
+			return errors.Wrapf(define.ErrInvalidArg, "%q is not a valid restart policy", policy)
+		}
+
+		pod.config.RestartRetries = retries
+
+		return nil
+	}
+}
+
 // WithVolatile sets the volatile flag for the container storage.
 // The option can potentially cause data loss when used on a container that must survive a machine reboot.
 func WithVolatile() CtrCreateOption {
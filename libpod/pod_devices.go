@@ -0,0 +1,57 @@
+package libpod
+
+import "strings"
+
+// MergePodDevices appends the pod-wide device list set by WithPodDevices to a
+// joining container's own --device list, de-duplicating by host path so a
+// container that also lists one of the pod's devices explicitly doesn't get
+// it twice. Order is preserved with the container's own entries first, since
+// they're the more specific request.
+func MergePodDevices(ctrDevices, podDevices []string) []string {
+	if len(podDevices) == 0 {
+		return ctrDevices
+	}
+
+	seen := make(map[string]bool, len(ctrDevices))
+	merged := make([]string, 0, len(ctrDevices)+len(podDevices))
+	// A device entry is "host[:container[:permissions]]"; key on the host part.
+	hostPath := func(d string) string { return strings.SplitN(d, ":", 2)[0] }
+	for _, d := range ctrDevices {
+		seen[hostPath(d)] = true
+		merged = append(merged, d)
+	}
+	for _, d := range podDevices {
+		if seen[hostPath(d)] {
+			continue
+		}
+		seen[hostPath(d)] = true
+		merged = append(merged, d)
+	}
+	return merged
+}
+
+// MergePodDeviceCgroupRules appends the pod-wide rules set by
+// WithPodDeviceCgroupRules to a joining container's own rules, the same way
+// MergePodDevices merges device nodes. Device cgroup rules are additive by
+// nature (each just widens what the cgroup allows), so no de-duplication
+// beyond exact-string matches is attempted.
+func MergePodDeviceCgroupRules(ctrRules, podRules []string) []string {
+	if len(podRules) == 0 {
+		return ctrRules
+	}
+
+	seen := make(map[string]bool, len(ctrRules))
+	merged := make([]string, 0, len(ctrRules)+len(podRules))
+	for _, r := range ctrRules {
+		seen[r] = true
+		merged = append(merged, r)
+	}
+	for _, r := range podRules {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		merged = append(merged, r)
+	}
+	return merged
+}
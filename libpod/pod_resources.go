@@ -0,0 +1,72 @@
+package libpod
+
+import (
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// MergePodResourceLimits combines the pod-wide ceiling set by WithPodResources
+// with a joining container's own requested limits, keeping the more
+// restrictive value wherever both are set. This is the computation the
+// container-create path needs to perform before writing the OCI spec for a
+// container joining a pod with WithPodResources applied; it is kept separate
+// from WithPodResources itself because the pod's limit must be resolved
+// against every member container individually, not just stored once.
+// A negative limit counts as unlimited. When both are non-nil, only the
+// memory limit, CPU quota and pids limit are combined; every other field,
+// block IO included, comes from ctrLimits alone and the pod's value for it
+// is not applied.
+func MergePodResourceLimits(podLimits, ctrLimits *specs.LinuxResources) *specs.LinuxResources {
+	if podLimits == nil {
+		return ctrLimits
+	}
+	if ctrLimits == nil {
+		return podLimits
+	}
+
+	merged := *ctrLimits
+
+	if podLimits.Memory != nil {
+		merged.Memory = mergeMemoryLimit(podLimits.Memory, merged.Memory)
+	}
+	if podLimits.CPU != nil {
+		merged.CPU = mergeCPULimit(podLimits.CPU, merged.CPU)
+	}
+	if podLimits.Pids != nil {
+		merged.Pids = mergePidsLimit(podLimits.Pids, merged.Pids)
+	}
+
+	return &merged
+}
+
+func mergeMemoryLimit(pod, ctr *specs.LinuxMemory) *specs.LinuxMemory {
+	if ctr == nil {
+		return pod
+	}
+	merged := *ctr
+	if pod.Limit != nil && *pod.Limit >= 0 && (merged.Limit == nil || *merged.Limit < 0 || *pod.Limit < *merged.Limit) {
+		merged.Limit = pod.Limit
+	}
+	return &merged
+}
+
+func mergeCPULimit(pod, ctr *specs.LinuxCPU) *specs.LinuxCPU {
+	if ctr == nil {
+		return pod
+	}
+	merged := *ctr
+	if pod.Quota != nil && *pod.Quota > 0 && (merged.Quota == nil || *merged.Quota <= 0 || *pod.Quota < *merged.Quota) {
+		merged.Quota = pod.Quota
+	}
+	return &merged
+}
+
+func mergePidsLimit(pod, ctr *specs.LinuxPids) *specs.LinuxPids {
+	if ctr == nil {
+		return pod
+	}
+	merged := *ctr
+	if pod.Limit > 0 && (merged.Limit <= 0 || pod.Limit < merged.Limit) {
+		merged.Limit = pod.Limit
+	}
+	return &merged
+}
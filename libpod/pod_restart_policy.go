@@ -0,0 +1,16 @@
+package libpod
+
+// ResolveContainerRestartPolicy decides the restart policy and retry count a
+// container joining a pod should run with, given the pod-wide default set by
+// WithPodRestartPolicy. A container that set its own policy explicitly (via
+// WithRestartPolicy) always keeps it; only a container that never called
+// WithRestartPolicy inherits the pod's policy. Pods created without
+// WithPodRestartPolicy leave podPolicy empty, so a container with no policy
+// of its own continues to fall through to ctrPolicy/ctrRetries unchanged -
+// i.e. the usual "no" default, same as outside of a pod.
+func ResolveContainerRestartPolicy(podPolicy string, podRetries uint, ctrPolicySet bool, ctrPolicy string, ctrRetries uint) (string, uint) {
+	if ctrPolicySet || podPolicy == "" {
+		return ctrPolicy, ctrRetries
+	}
+	return podPolicy, podRetries
+}
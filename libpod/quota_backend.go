@@ -0,0 +1,101 @@
+package libpod
+
+import (
+	"fmt"
+	"sync"
+)
+
+// QuotaUsage reports current consumption against a volume's configured
+// quota, surfaced through `podman volume inspect`.
+type QuotaUsage struct {
+	// UsedBytes is the space currently consumed, in bytes.
+	UsedBytes uint64
+	// LimitBytes is the configured limit (WithVolumeSize), in bytes. 0
+	// means no limit was configured.
+	LimitBytes uint64
+	// UsedInodes is the number of inodes currently consumed.
+	UsedInodes uint64
+	// LimitInodes is the configured limit (WithVolumeInodes). 0 means no
+	// limit was configured.
+	LimitInodes uint64
+}
+
+// QuotaBackend enforces the size/inode limits set via WithVolumeSize and
+// WithVolumeInodes. Selection happens automatically by inspecting the
+// filesystem underlying a volume's path; backends must return a clear error
+// rather than silently ignoring limits when the filesystem does not support
+// quotas.
+type QuotaBackend interface {
+	// Name identifies the backend for logging and error messages (e.g.
+	// "xfs", "btrfs", "tmpfs").
+	Name() string
+	// Supports reports whether this backend can enforce quotas for the
+	// filesystem mounted at path.
+	Supports(path string) (bool, error)
+	// Apply allocates whatever backend-specific resource is needed (e.g.
+	// an XFS project ID, a btrfs qgroup) for vol and enforces its
+	// configured Size/Inodes limits. Called once, after the volume
+	// directory has been created.
+	Apply(vol *Volume) error
+	// Refresh re-applies the limits, e.g. after the volume filesystem has
+	// been (re)mounted.
+	Refresh(vol *Volume) error
+	// Release tears down whatever Apply allocated. Called on volume
+	// removal.
+	Release(vol *Volume) error
+	// Usage reports current consumption against the configured limits.
+	Usage(vol *Volume) (*QuotaUsage, error)
+}
+
+var (
+	quotaBackendsLock sync.Mutex
+	quotaBackends     []QuotaBackend
+)
+
+// RegisterQuotaBackend adds backend to the list consulted by
+// SelectQuotaBackend. Built-in backends (XFS project quotas, btrfs qgroups,
+// tmpfs mount options) register themselves from init().
+func RegisterQuotaBackend(backend QuotaBackend) {
+	quotaBackendsLock.Lock()
+	defer quotaBackendsLock.Unlock()
+
+	quotaBackends = append(quotaBackends, backend)
+}
+
+// SelectQuotaBackend picks the first registered QuotaBackend that reports
+// support for the filesystem underlying path. It returns an error - rather
+// than silently skipping enforcement - when a volume has a configured
+// Size/Inodes limit but no backend supports its filesystem.
+func SelectQuotaBackend(path string) (QuotaBackend, error) {
+	quotaBackendsLock.Lock()
+	backends := make([]QuotaBackend, len(quotaBackends))
+	copy(backends, quotaBackends)
+	quotaBackendsLock.Unlock()
+
+	for _, backend := range backends {
+		ok, err := backend.Supports(path)
+		if err != nil {
+			return nil, fmt.Errorf("probing %s quota backend for %q: %w", backend.Name(), path, err)
+		}
+		if ok {
+			return backend, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no quota backend supports the filesystem at %q; volume size/inode limits cannot be enforced", path)
+}
+
+// ApplyVolumeQuota selects and applies a QuotaBackend for vol if it has a
+// Size or Inodes limit configured. Volumes without either limit are left
+// alone - there's nothing to enforce.
+func ApplyVolumeQuota(vol *Volume) error {
+	if vol.config.Size == 0 && vol.config.Inodes == 0 {
+		return nil
+	}
+
+	backend, err := SelectQuotaBackend(vol.config.MountPoint)
+	if err != nil {
+		return err
+	}
+	return backend.Apply(vol)
+}
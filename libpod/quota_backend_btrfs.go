@@ -0,0 +1,124 @@
+package libpod
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+func init() {
+	RegisterQuotaBackend(&btrfsQuotaBackend{})
+}
+
+// btrfsQuotaBackend enforces volume limits using btrfs subvolume qgroups.
+// Unlike XFS project quotas, this requires the volume path itself to be a
+// subvolume (or to be turned into one), since qgroups are keyed off
+// subvolume IDs rather than arbitrary directories.
+type btrfsQuotaBackend struct{}
+
+func (b *btrfsQuotaBackend) Name() string {
+	return "btrfs"
+}
+
+func (b *btrfsQuotaBackend) Supports(path string) (bool, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return false, fmt.Errorf("statfs %q: %w", path, err)
+	}
+	return stat.Type == unix.BTRFS_SUPER_MAGIC, nil
+}
+
+// Apply is called after the volume directory already exists (see
+// QuotaBackend.Apply), but `btrfs subvolume create` refuses to create a
+// subvolume at a path that's already there. Since a qgroup is keyed off a
+// subvolume ID, the plain directory has to be removed and replaced with a
+// subvolume of the same name before quotas can be applied to it.
+func (b *btrfsQuotaBackend) Apply(vol *Volume) error {
+	if vol.config.Inodes > 0 {
+		return fmt.Errorf("btrfs qgroups cannot enforce an inode limit for volume %q", vol.config.Name)
+	}
+	empty, err := isEmptyDir(vol.config.MountPoint)
+	if err != nil {
+		return fmt.Errorf("checking volume directory %q: %w", vol.config.MountPoint, err)
+	}
+	if !empty {
+		return fmt.Errorf("cannot convert non-empty volume directory %q into a btrfs subvolume", vol.config.MountPoint)
+	}
+	if err := os.Remove(vol.config.MountPoint); err != nil {
+		return fmt.Errorf("removing volume directory %q to replace it with a btrfs subvolume: %w", vol.config.MountPoint, err)
+	}
+	if err := run("btrfs", "subvolume", "create", vol.config.MountPoint); err != nil {
+		return fmt.Errorf("creating btrfs subvolume for %q: %w", vol.config.MountPoint, err)
+	}
+	if err := run("btrfs", "quota", "enable", vol.config.MountPoint); err != nil {
+		return fmt.Errorf("enabling btrfs quotas for %q: %w", vol.config.MountPoint, err)
+	}
+	return b.Refresh(vol)
+}
+
+func (b *btrfsQuotaBackend) Refresh(vol *Volume) error {
+	if vol.config.Size == 0 {
+		return nil
+	}
+	limit := strconv.FormatUint(vol.config.Size, 10)
+	if err := run("btrfs", "qgroup", "limit", limit, vol.config.MountPoint); err != nil {
+		return fmt.Errorf("setting btrfs qgroup limit on %q: %w", vol.config.MountPoint, err)
+	}
+	return nil
+}
+
+func (b *btrfsQuotaBackend) Release(vol *Volume) error {
+	// Removing the subvolume (done elsewhere in the volume removal path)
+	// also tears down its qgroup; nothing additional to release here.
+	return nil
+}
+
+func (b *btrfsQuotaBackend) Usage(vol *Volume) (*QuotaUsage, error) {
+	out, err := exec.Command("btrfs", "qgroup", "show", "--raw", vol.config.MountPoint).Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading btrfs qgroup usage for %q: %w", vol.config.MountPoint, err)
+	}
+
+	usage := &QuotaUsage{LimitBytes: vol.config.Size, LimitInodes: vol.config.Inodes}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasPrefix(fields[0], "0/") {
+			continue
+		}
+		if used, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			usage.UsedBytes = used
+		}
+	}
+	return usage, nil
+}
+
+func run(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, string(out))
+	}
+	return nil
+}
+
+// isEmptyDir reports whether path is a directory with no entries.
+func isEmptyDir(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	_, err = f.Readdirnames(1)
+	if err == io.EOF {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
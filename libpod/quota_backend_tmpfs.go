@@ -0,0 +1,87 @@
+package libpod
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+func init() {
+	RegisterQuotaBackend(&tmpfsQuotaBackend{})
+}
+
+// tmpfsQuotaBackend enforces volume limits for volumes backed by a tmpfs
+// mount, using the size= and nr_inodes= mount options rather than a
+// separate quota mechanism. Unlike the XFS and btrfs backends, the limit is
+// fixed at mount time and Refresh/Release are no-ops - remounting with new
+// options is the only way to change it.
+type tmpfsQuotaBackend struct{}
+
+func (b *tmpfsQuotaBackend) Name() string {
+	return "tmpfs"
+}
+
+func (b *tmpfsQuotaBackend) Supports(path string) (bool, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return false, fmt.Errorf("statfs %q: %w", path, err)
+	}
+	return stat.Type == unix.TMPFS_MAGIC, nil
+}
+
+func (b *tmpfsQuotaBackend) Apply(vol *Volume) error {
+	data := tmpfsMountData(vol)
+	if data == "" {
+		return nil
+	}
+	if err := unix.Mount("tmpfs", vol.config.MountPoint, "tmpfs", unix.MS_REMOUNT, data); err != nil {
+		return fmt.Errorf("remounting tmpfs volume %q with %q: %w", vol.config.MountPoint, data, err)
+	}
+	return nil
+}
+
+// Refresh is a no-op: tmpfs size/inode limits are a mount option, already in
+// effect for as long as the mount exists.
+func (b *tmpfsQuotaBackend) Refresh(vol *Volume) error {
+	return nil
+}
+
+// Release is a no-op: the limit goes away with the mount itself, which is
+// torn down elsewhere in the volume removal path.
+func (b *tmpfsQuotaBackend) Release(vol *Volume) error {
+	return nil
+}
+
+func (b *tmpfsQuotaBackend) Usage(vol *Volume) (*QuotaUsage, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(vol.config.MountPoint, &stat); err != nil {
+		return nil, fmt.Errorf("statfs %q: %w", vol.config.MountPoint, err)
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return &QuotaUsage{
+		UsedBytes:   (uint64(stat.Blocks) - uint64(stat.Bfree)) * blockSize,
+		LimitBytes:  vol.config.Size,
+		UsedInodes:  uint64(stat.Files) - uint64(stat.Ffree),
+		LimitInodes: vol.config.Inodes,
+	}, nil
+}
+
+func tmpfsMountData(vol *Volume) string {
+	var opts []string
+	if vol.config.Size > 0 {
+		opts = append(opts, "size="+strconv.FormatUint(vol.config.Size, 10))
+	}
+	if vol.config.Inodes > 0 {
+		opts = append(opts, "nr_inodes="+strconv.FormatUint(vol.config.Inodes, 10))
+	}
+	if len(opts) == 0 {
+		return ""
+	}
+	data := opts[0]
+	for _, o := range opts[1:] {
+		data += "," + o
+	}
+	return data
+}
@@ -0,0 +1,26 @@
+package libpod
+
+import "testing"
+
+func TestTmpfsMountData(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   uint64
+		inodes uint64
+		want   string
+	}{
+		{name: "no limits", want: ""},
+		{name: "size only", size: 1024, want: "size=1024"},
+		{name: "inodes only", inodes: 100, want: "nr_inodes=100"},
+		{name: "size and inodes", size: 1024, inodes: 100, want: "size=1024,nr_inodes=100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vol := &Volume{config: &VolumeConfig{Size: tt.size, Inodes: tt.inodes}}
+			if got := tmpfsMountData(vol); got != tt.want {
+				t.Errorf("tmpfsMountData() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package libpod
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func init() {
+	RegisterQuotaBackend(&xfsQuotaBackend{})
+}
+
+// xfsQuotaBackend enforces volume limits using XFS project quotas: each
+// volume is assigned a project ID via the FS_IOC_FSSETXATTR ioctl, and the
+// size/inode limits are set on that project via the Q_XSETQLIM quotactl(2)
+// command against the backing block device.
+type xfsQuotaBackend struct {
+	mu sync.Mutex
+}
+
+func (b *xfsQuotaBackend) Name() string {
+	return "xfs"
+}
+
+func (b *xfsQuotaBackend) Supports(path string) (bool, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return false, fmt.Errorf("statfs %q: %w", path, err)
+	}
+	if stat.Type != unix.XFS_SUPER_MAGIC {
+		return false, nil
+	}
+	if _, err := blockDeviceFor(path); err != nil {
+		return false, nil
+	}
+	// pquota/prjquota must be enabled on the mount for project quotas to
+	// work at all; without it the ioctl/quotactl calls below fail with
+	// ENOSYS, which is a poor substitute for a clear "unsupported" error.
+	enabled, err := xfsProjectQuotaEnabled(path)
+	if err != nil {
+		return false, fmt.Errorf("checking project quota mount option for %q: %w", path, err)
+	}
+	return enabled, nil
+}
+
+func (b *xfsQuotaBackend) Apply(vol *Volume) error {
+	projectID, err := b.allocateProjectID(vol)
+	if err != nil {
+		return fmt.Errorf("allocating XFS project ID for %q: %w", vol.config.MountPoint, err)
+	}
+	vol.config.StorageOpts = withStorageOpt(vol.config.StorageOpts, "xfs.projectID", fmt.Sprintf("%d", projectID))
+
+	return b.setQuota(vol, projectID)
+}
+
+func (b *xfsQuotaBackend) Refresh(vol *Volume) error {
+	projectID, err := b.projectIDFor(vol)
+	if err != nil {
+		return err
+	}
+	return b.setQuota(vol, projectID)
+}
+
+func (b *xfsQuotaBackend) Release(vol *Volume) error {
+	projectID, err := b.projectIDFor(vol)
+	if err != nil {
+		// Nothing was ever allocated; nothing to release.
+		return nil
+	}
+	return xfsSetProjectQuota(vol.config.MountPoint, projectID, 0, 0)
+}
+
+func (b *xfsQuotaBackend) Usage(vol *Volume) (*QuotaUsage, error) {
+	projectID, err := b.projectIDFor(vol)
+	if err != nil {
+		return nil, err
+	}
+	return xfsGetProjectQuotaUsage(vol.config.MountPoint, projectID)
+}
+
+func (b *xfsQuotaBackend) setQuota(vol *Volume, projectID uint32) error {
+	if err := xfsSetProjectID(vol.config.MountPoint, projectID); err != nil {
+		return fmt.Errorf("assigning XFS project %d to %q: %w", projectID, vol.config.MountPoint, err)
+	}
+	if err := xfsSetProjectQuota(vol.config.MountPoint, projectID, vol.config.Size, vol.config.Inodes); err != nil {
+		return fmt.Errorf("setting XFS project quota for %q: %w", vol.config.MountPoint, err)
+	}
+	return nil
+}
+
+// xfsProjectIDStateFile is the on-disk high-water mark used to allocate XFS
+// project IDs. It lives next to the volume itself (one file per volumes
+// directory, shared by every volume backed by that filesystem) so every
+// podman process allocates from the same sequence instead of each process
+// starting back at 100 and colliding with a project ID an earlier process
+// already assigned to a different volume.
+const xfsProjectIDStateFile = ".xfs-project-id-highwater"
+
+// allocateProjectID reserves the next unused XFS project ID for vol's
+// backing filesystem. The high-water mark is persisted in a file locked with
+// flock(2) for the duration of the read-increment-write, so concurrent
+// podman processes allocating at the same time still get distinct IDs.
+func (b *xfsQuotaBackend) allocateProjectID(vol *Volume) (uint32, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statePath := filepath.Join(filepath.Dir(vol.config.MountPoint), xfsProjectIDStateFile)
+
+	f, err := os.OpenFile(statePath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("opening XFS project ID state file %q: %w", statePath, err)
+	}
+	defer f.Close()
+
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX); err != nil {
+		return 0, fmt.Errorf("locking XFS project ID state file %q: %w", statePath, err)
+	}
+	defer unix.Flock(int(f.Fd()), unix.LOCK_UN)
+
+	// Project IDs 0-99 are conventionally reserved for system use; start
+	// allocation above that range.
+	high := uint32(99)
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		return 0, fmt.Errorf("reading XFS project ID state file %q: %w", statePath, err)
+	}
+	if trimmed := strings.TrimSpace(string(raw)); trimmed != "" {
+		parsed, err := strconv.ParseUint(trimmed, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid XFS project ID high-water mark %q in %q: %w", trimmed, statePath, err)
+		}
+		if uint32(parsed) > high {
+			high = uint32(parsed)
+		}
+	}
+
+	id := high + 1
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("rewinding XFS project ID state file %q: %w", statePath, err)
+	}
+	if err := f.Truncate(0); err != nil {
+		return 0, fmt.Errorf("truncating XFS project ID state file %q: %w", statePath, err)
+	}
+	if _, err := f.WriteString(strconv.FormatUint(uint64(id), 10)); err != nil {
+		return 0, fmt.Errorf("writing XFS project ID state file %q: %w", statePath, err)
+	}
+
+	return id, nil
+}
+
+func (b *xfsQuotaBackend) projectIDFor(vol *Volume) (uint32, error) {
+	raw, ok := vol.config.StorageOpts["xfs.projectID"]
+	if !ok {
+		return 0, fmt.Errorf("volume %q has no XFS project ID recorded", vol.config.Name)
+	}
+	var id uint32
+	if _, err := fmt.Sscanf(raw, "%d", &id); err != nil {
+		return 0, fmt.Errorf("invalid XFS project ID %q for volume %q: %w", raw, vol.config.Name, err)
+	}
+	return id, nil
+}
+
+func withStorageOpt(opts map[string]string, key, value string) map[string]string {
+	if opts == nil {
+		opts = make(map[string]string, 1)
+	}
+	opts[key] = value
+	return opts
+}
+
+// fsxattr mirrors struct fsxattr from linux/fs.h, the payload of the
+// FS_IOC_FSGETXATTR/FS_IOC_FSSETXATTR ioctls used to assign a directory to
+// an XFS project.
+type fsxattr struct {
+	xflags     uint32
+	extsize    uint32
+	nextents   uint32
+	projid     uint32
+	cowextsize uint32
+	pad        [8]byte
+}
+
+const (
+	// fsIocFsgetxattr/fsIocFssetxattr are _IOR('X', 31, ...)/_IOW('X', 32, ...)
+	// as defined by linux/fs.h; Go has no ioctl macro helpers, so the
+	// encoded values are reproduced directly.
+	fsIocFsgetxattr = 0x801c581f
+	fsIocFssetxattr = 0x401c5820
+
+	// fsXflagProjinherit marks a directory so files created under it
+	// inherit its project ID, required for project quotas to track an
+	// entire volume rather than just its top-level directory.
+	fsXflagProjinherit = 0x00000200
+)
+
+// xfsSetProjectID assigns path to projectID via the FS_IOC_FSSETXATTR ioctl,
+// setting FS_XFLAG_PROJINHERIT so everything created under path inherits the
+// same project.
+func xfsSetProjectID(path string, projectID uint32) error {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_DIRECTORY, 0)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var attr fsxattr
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), fsIocFsgetxattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return fmt.Errorf("FS_IOC_FSGETXATTR on %q: %w", path, errno)
+	}
+
+	attr.projid = projectID
+	attr.xflags |= fsXflagProjinherit
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), fsIocFssetxattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return fmt.Errorf("FS_IOC_FSSETXATTR on %q: %w", path, errno)
+	}
+	return nil
+}
+
+// fsDiskQuota mirrors struct fs_disk_quota (aka xfs_dqblk) from
+// linux/dqblk_xfs.h, the payload of the Q_XGETQUOTA/Q_XSETQLIM quotactl(2)
+// commands.
+type fsDiskQuota struct {
+	version      int8
+	flags        int8
+	fieldmask    uint16
+	id           uint32
+	blkHardlimit uint64
+	blkSoftlimit uint64
+	inoHardlimit uint64
+	inoSoftlimit uint64
+	bcount       uint64
+	icount       uint64
+	itimer       int32
+	btimer       int32
+	iwarns       uint16
+	bwarns       uint16
+	padding2     int32
+	rtbHardlimit uint64
+	rtbSoftlimit uint64
+	rtbcount     uint64
+	rtbtimer     int32
+	rtbwarns     uint16
+	padding3     int16
+	padding4     [8]byte
+}
+
+const (
+	fsDqoutVersion = 1
+	fsProjQuota    = 2 // d_flags: this is a project quota, not user/group
+
+	fsDqISoft = 0x1
+	fsDqIHard = 0x2
+	fsDqBSoft = 0x4
+	fsDqBHard = 0x8
+
+	prjQuotaType = 2 // PRJQUOTA
+
+	// qXgetquota/qXsetqlim are the XFS-specific quotactl subcommands
+	// (XQM_CMD(3) and XQM_CMD(4) in linux/dqblk_xfs.h); QCMD folds in the
+	// quota type the same way the libc QCMD() macro does.
+	qXgetquota = 0x5803
+	qXsetqlim  = 0x5804
+)
+
+func xfsQcmd(subcmd int) int {
+	return (subcmd << 8) | prjQuotaType
+}
+
+func xfsSetProjectQuota(path string, projectID uint32, sizeBytes, inodes uint64) error {
+	device, err := blockDeviceFor(path)
+	if err != nil {
+		return fmt.Errorf("resolving backing device for %q: %w", path, err)
+	}
+
+	// Both limits are always sent; XFS treats a zero limit as "no limit",
+	// so Release (which passes 0, 0) clears whatever Apply set.
+	blocks := sizeBytes / 512 // the XFS quota ABI counts in basic blocks (512B)
+	quota := fsDiskQuota{
+		version:      fsDqoutVersion,
+		flags:        fsProjQuota,
+		fieldmask:    fsDqBHard | fsDqBSoft | fsDqIHard | fsDqISoft,
+		id:           projectID,
+		blkHardlimit: blocks,
+		blkSoftlimit: blocks,
+		inoHardlimit: inodes,
+		inoSoftlimit: inodes,
+	}
+
+	return quotactl(xfsQcmd(qXsetqlim), device, int(projectID), unsafe.Pointer(&quota))
+}
+
+func xfsGetProjectQuotaUsage(path string, projectID uint32) (*QuotaUsage, error) {
+	device, err := blockDeviceFor(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving backing device for %q: %w", path, err)
+	}
+
+	var quota fsDiskQuota
+	if err := quotactl(xfsQcmd(qXgetquota), device, int(projectID), unsafe.Pointer(&quota)); err != nil {
+		return nil, fmt.Errorf("Q_XGETQUOTA for project %d on %q: %w", projectID, device, err)
+	}
+
+	return &QuotaUsage{
+		UsedBytes:   quota.bcount * 512,
+		LimitBytes:  quota.blkHardlimit * 512,
+		UsedInodes:  quota.icount,
+		LimitInodes: quota.inoHardlimit,
+	}, nil
+}
+
+// quotactl wraps the quotactl(2) syscall, which golang.org/x/sys/unix does
+// not expose a helper for (its Quotactl wrapper only covers the Linux
+// Dqblk/ext4 quota format, not XFS project quotas).
+func quotactl(cmd int, special string, id int, addr unsafe.Pointer) error {
+	path, err := unix.BytePtrFromString(special)
+	if err != nil {
+		return err
+	}
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, uintptr(cmd), uintptr(unsafe.Pointer(path)), uintptr(id), uintptr(addr), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// mountinfoEntry holds the fields of a /proc/self/mountinfo line that
+// findMountFor callers care about: the mount point, the backing device, and
+// the per-mount super options (the fstype-specific options after the "-"
+// separator, e.g. "rw,prjquota").
+type mountinfoEntry struct {
+	mountPoint string
+	device     string
+	superOpts  string
+}
+
+// findMountFor scans /proc/self/mountinfo for the entry whose mount point is
+// the longest prefix of path - the same approach `df` and `findmnt` use
+// internally. A mount point only matches if path is exactly that mount point
+// or path descends into it via a "/" component boundary, so e.g. a mount at
+// "/data" does not spuriously match a path like "/database/x".
+func findMountFor(path string) (mountinfoEntry, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return mountinfoEntry{}, err
+	}
+	defer f.Close()
+
+	var best mountinfoEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, field := range fields {
+			if field == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 0 || sep+3 >= len(fields) || len(fields) < 5 {
+			continue
+		}
+		mountPoint := fields[4]
+		if !(mountPoint == "/" || path == mountPoint || strings.HasPrefix(path, mountPoint+"/")) {
+			continue
+		}
+		if len(mountPoint) > len(best.mountPoint) {
+			best = mountinfoEntry{mountPoint: mountPoint, device: fields[sep+2], superOpts: fields[sep+3]}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return mountinfoEntry{}, err
+	}
+	if best.mountPoint == "" {
+		return mountinfoEntry{}, fmt.Errorf("no mount found for %q", path)
+	}
+	return best, nil
+}
+
+// blockDeviceFor returns the backing block device of the filesystem mounted
+// at path.
+func blockDeviceFor(path string) (string, error) {
+	mount, err := findMountFor(path)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(mount.device, "/dev/") {
+		return "", fmt.Errorf("filesystem at %q is not backed by a block device (%s)", path, mount.device)
+	}
+	return mount.device, nil
+}
+
+// xfsProjectQuotaEnabled reports whether the filesystem mounted at path was
+// mounted with the prjquota/pquota option. Without it, the ioctl/quotactl
+// calls this backend relies on fail with ENOSYS, so Supports probes for the
+// option directly rather than assuming it from the filesystem type alone.
+func xfsProjectQuotaEnabled(path string) (bool, error) {
+	mount, err := findMountFor(path)
+	if err != nil {
+		return false, err
+	}
+	for _, opt := range strings.Split(mount.superOpts, ",") {
+		if opt == "prjquota" || opt == "pquota" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
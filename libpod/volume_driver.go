@@ -0,0 +1,80 @@
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/containers/podman/v3/libpod/define"
+	"github.com/pkg/errors"
+)
+
+// localVolumeDriver is the name reserved for Podman's built-in driver, which
+// is handled directly by the Volume type and never goes through the
+// VolumeDriver registry.
+const localVolumeDriver = "local"
+
+// VolumeDriver is the interface implemented by pluggable volume backends.
+// Podman's built-in "local" driver is handled directly by the Volume type
+// and does not go through this interface; everything else - Docker Volume
+// Plugin HTTP backends and CSI node plugins - is reached through a
+// VolumeDriver registered under its name.
+type VolumeDriver interface {
+	// Name returns the driver's registered name.
+	Name() string
+	// Create provisions a new volume. opts are the values passed via
+	// WithVolumeOptions.
+	Create(ctx context.Context, volName string, opts map[string]string) error
+	// Remove tears down a volume previously created by this driver.
+	Remove(ctx context.Context, volName string) error
+	// Mount stages the volume for use and returns the path containers
+	// should bind-mount from.
+	Mount(ctx context.Context, volName, id string) (mountpoint string, err error)
+	// Unmount undoes Mount. The volume may still be referenced by other
+	// containers, in which case the driver is responsible for refcounting.
+	Unmount(ctx context.Context, volName, id string) error
+	// Get returns driver-reported metadata about the volume, notably its
+	// current mountpoint if any.
+	Get(ctx context.Context, volName string) (mountpoint string, err error)
+	// List returns the names of all volumes the driver knows about.
+	List(ctx context.Context) ([]string, error)
+	// Capabilities reports backend-specific characteristics (e.g. whether
+	// the driver supports scoping to a single host) for diagnostics.
+	Capabilities(ctx context.Context) (map[string]string, error)
+}
+
+var (
+	volumeDriversLock sync.Mutex
+	volumeDrivers     = make(map[string]VolumeDriver)
+)
+
+// RegisterVolumeDriver makes a VolumeDriver available under its Name() to
+// WithVolumeDriver. Adapters are expected to register themselves from an
+// init() function.
+func RegisterVolumeDriver(driver VolumeDriver) error {
+	volumeDriversLock.Lock()
+	defer volumeDriversLock.Unlock()
+
+	name := driver.Name()
+	if name == "" || name == localVolumeDriver {
+		return errors.Errorf("volume driver name %q is reserved", name)
+	}
+	if _, ok := volumeDrivers[name]; ok {
+		return errors.Errorf("volume driver %q is already registered", name)
+	}
+
+	volumeDrivers[name] = driver
+	return nil
+}
+
+// GetVolumeDriver looks up a previously-registered VolumeDriver by name.
+func GetVolumeDriver(name string) (VolumeDriver, error) {
+	volumeDriversLock.Lock()
+	defer volumeDriversLock.Unlock()
+
+	driver, ok := volumeDrivers[name]
+	if !ok {
+		return nil, fmt.Errorf("no volume driver registered for %q: %w", name, define.ErrNoSuchVolume)
+	}
+	return driver, nil
+}
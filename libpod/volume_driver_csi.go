@@ -0,0 +1,143 @@
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// csiNodeDriver shells out to a CSI node plugin over its gRPC unix socket.
+// Only the Node service is used - Podman has no controller/scheduler concept,
+// so volumes are expected to already be provisioned (e.g. by a CSI
+// controller elsewhere, or statically) and this driver only stages/publishes
+// them on the local host.
+type csiNodeDriver struct {
+	name string
+	conn *grpc.ClientConn
+	node csi.NodeClient
+
+	// mountPointsMu guards mountPoints. The CSI Node service has no RPC
+	// that reports a volume's current mountpoint - NodeGetVolumeStats
+	// reports usage/health, not a path - so Get serves the last path this
+	// driver itself published via Mount.
+	mountPointsMu sync.Mutex
+	mountPoints   map[string]string
+}
+
+// NewCSINodeDriver returns a VolumeDriver that talks to the CSI node plugin
+// listening on socketPath, registered under name.
+func NewCSINodeDriver(name, socketPath string) (VolumeDriver, error) {
+	conn, err := grpc.Dial("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("dialing CSI node plugin %q at %q: %w", name, socketPath, err)
+	}
+
+	return &csiNodeDriver{
+		name:        name,
+		conn:        conn,
+		node:        csi.NewNodeClient(conn),
+		mountPoints: make(map[string]string),
+	}, nil
+}
+
+func (d *csiNodeDriver) Name() string {
+	return d.name
+}
+
+// Create is a no-op for CSI: volumes are provisioned out of band (by a CSI
+// controller, or statically) and only staged/published here.
+func (d *csiNodeDriver) Create(ctx context.Context, volName string, opts map[string]string) error {
+	return nil
+}
+
+// Remove is a no-op for CSI, for the same reason Create is.
+func (d *csiNodeDriver) Remove(ctx context.Context, volName string) error {
+	return nil
+}
+
+func (d *csiNodeDriver) Mount(ctx context.Context, volName, id string) (string, error) {
+	targetPath := d.containerMountPath(volName, id)
+
+	_, err := d.node.NodePublishVolume(ctx, &csi.NodePublishVolumeRequest{
+		VolumeId:   volName,
+		TargetPath: targetPath,
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("NodePublishVolume for %q on CSI plugin %q: %w", volName, d.name, err)
+	}
+
+	d.mountPointsMu.Lock()
+	d.mountPoints[volName] = targetPath
+	d.mountPointsMu.Unlock()
+
+	return targetPath, nil
+}
+
+func (d *csiNodeDriver) Unmount(ctx context.Context, volName, id string) error {
+	targetPath := d.containerMountPath(volName, id)
+
+	_, err := d.node.NodeUnpublishVolume(ctx, &csi.NodeUnpublishVolumeRequest{
+		VolumeId:   volName,
+		TargetPath: targetPath,
+	})
+	if err != nil {
+		return fmt.Errorf("NodeUnpublishVolume for %q on CSI plugin %q: %w", volName, d.name, err)
+	}
+
+	d.mountPointsMu.Lock()
+	if d.mountPoints[volName] == targetPath {
+		delete(d.mountPoints, volName)
+	}
+	d.mountPointsMu.Unlock()
+
+	return nil
+}
+
+// Get returns volName's current mountpoint, as tracked from the last Mount
+// this driver instance performed for it. It does not call into the CSI node
+// plugin: NodeGetVolumeStats reports usage/health, not a path, and CSI has
+// no RPC for querying a previously-published mountpoint.
+func (d *csiNodeDriver) Get(ctx context.Context, volName string) (string, error) {
+	d.mountPointsMu.Lock()
+	defer d.mountPointsMu.Unlock()
+
+	mountpoint, ok := d.mountPoints[volName]
+	if !ok {
+		return "", fmt.Errorf("volume %q is not currently mounted by CSI plugin %q", volName, d.name)
+	}
+	return mountpoint, nil
+}
+
+// List is unsupported by the CSI Node service; CSI has no volume enumeration
+// API that doesn't require the Controller service, which this driver does
+// not speak.
+func (d *csiNodeDriver) List(ctx context.Context) ([]string, error) {
+	return nil, fmt.Errorf("volume driver %q: listing volumes is not supported by the CSI node service", d.name)
+}
+
+func (d *csiNodeDriver) Capabilities(ctx context.Context) (map[string]string, error) {
+	resp, err := d.node.NodeGetCapabilities(ctx, &csi.NodeGetCapabilitiesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("NodeGetCapabilities on CSI plugin %q: %w", d.name, err)
+	}
+
+	caps := make(map[string]string, len(resp.GetCapabilities()))
+	for _, c := range resp.GetCapabilities() {
+		if rpc := c.GetRpc(); rpc != nil {
+			caps[rpc.GetType().String()] = "true"
+		}
+	}
+	return caps, nil
+}
+
+func (d *csiNodeDriver) containerMountPath(volName, id string) string {
+	return fmt.Sprintf("/var/lib/containers/storage/volumes/%s/csi/%s", volName, id)
+}
@@ -0,0 +1,177 @@
+package libpod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// dockerPluginDriver speaks the Docker Volume Plugin HTTP protocol over a
+// unix socket, so existing third-party plugins (local-persist, sshfs,
+// rexray, ...) keep working unmodified against Podman.
+type dockerPluginDriver struct {
+	name       string
+	socketPath string
+	client     *http.Client
+}
+
+// NewDockerPluginDriver returns a VolumeDriver backed by a Docker Volume
+// Plugin listening on socketPath, registered under name.
+func NewDockerPluginDriver(name, socketPath string) VolumeDriver {
+	return &dockerPluginDriver{
+		name:       name,
+		socketPath: socketPath,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+func (d *dockerPluginDriver) Name() string {
+	return d.name
+}
+
+func (d *dockerPluginDriver) Create(ctx context.Context, volName string, opts map[string]string) error {
+	_, err := d.call(ctx, "/VolumeDriver.Create", map[string]interface{}{
+		"Name": volName,
+		"Opts": opts,
+	})
+	return err
+}
+
+func (d *dockerPluginDriver) Remove(ctx context.Context, volName string) error {
+	_, err := d.call(ctx, "/VolumeDriver.Remove", map[string]interface{}{"Name": volName})
+	return err
+}
+
+func (d *dockerPluginDriver) Mount(ctx context.Context, volName, id string) (string, error) {
+	resp, err := d.call(ctx, "/VolumeDriver.Mount", map[string]interface{}{"Name": volName, "ID": id})
+	if err != nil {
+		return "", err
+	}
+	return mountpointFromResponse(resp)
+}
+
+func (d *dockerPluginDriver) Unmount(ctx context.Context, volName, id string) error {
+	_, err := d.call(ctx, "/VolumeDriver.Unmount", map[string]interface{}{"Name": volName, "ID": id})
+	return err
+}
+
+func (d *dockerPluginDriver) Get(ctx context.Context, volName string) (string, error) {
+	resp, err := d.call(ctx, "/VolumeDriver.Get", map[string]interface{}{"Name": volName})
+	if err != nil {
+		return "", err
+	}
+
+	// Unlike Mount, the Get response nests the mountpoint under "Volume".
+	var parsed struct {
+		Volume struct {
+			Mountpoint string `json:"Mountpoint"`
+		} `json:"Volume"`
+		Err string `json:"Err"`
+	}
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return "", fmt.Errorf("decoding VolumeDriver.Get response from %q: %w", d.name, err)
+	}
+	if parsed.Err != "" {
+		return "", fmt.Errorf("volume driver %q: %s", d.name, parsed.Err)
+	}
+	return parsed.Volume.Mountpoint, nil
+}
+
+func (d *dockerPluginDriver) List(ctx context.Context) ([]string, error) {
+	resp, err := d.call(ctx, "/VolumeDriver.List", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Volumes []struct {
+			Name string `json:"Name"`
+		} `json:"Volumes"`
+		Err string `json:"Err"`
+	}
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return nil, fmt.Errorf("decoding VolumeDriver.List response from %q: %w", d.name, err)
+	}
+	if parsed.Err != "" {
+		return nil, fmt.Errorf("volume driver %q: %s", d.name, parsed.Err)
+	}
+
+	names := make([]string, 0, len(parsed.Volumes))
+	for _, v := range parsed.Volumes {
+		names = append(names, v.Name)
+	}
+	return names, nil
+}
+
+func (d *dockerPluginDriver) Capabilities(ctx context.Context) (map[string]string, error) {
+	resp, err := d.call(ctx, "/VolumeDriver.Capabilities", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Capabilities map[string]string `json:"Capabilities"`
+		Err          string            `json:"Err"`
+	}
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return nil, fmt.Errorf("decoding VolumeDriver.Capabilities response from %q: %w", d.name, err)
+	}
+	if parsed.Err != "" {
+		return nil, fmt.Errorf("volume driver %q: %s", d.name, parsed.Err)
+	}
+	return parsed.Capabilities, nil
+}
+
+func (d *dockerPluginDriver) call(ctx context.Context, path string, body map[string]interface{}) ([]byte, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://plugin"+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s on volume plugin %q: %w", path, d.name, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("volume plugin %q returned %s for %s: %s", d.name, resp.Status, path, string(respBody))
+	}
+	return respBody, nil
+}
+
+func mountpointFromResponse(resp []byte) (string, error) {
+	var parsed struct {
+		Mountpoint string `json:"Mountpoint"`
+		Err        string `json:"Err"`
+	}
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return "", fmt.Errorf("decoding volume plugin response: %w", err)
+	}
+	if parsed.Err != "" {
+		return "", fmt.Errorf("volume plugin: %s", parsed.Err)
+	}
+	return parsed.Mountpoint, nil
+}
@@ -0,0 +1,35 @@
+package libpod
+
+import "testing"
+
+func TestMountpointFromResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    string
+		want    string
+		wantErr bool
+	}{
+		{name: "mountpoint", resp: `{"Mountpoint":"/var/lib/plugin/vol1"}`, want: "/var/lib/plugin/vol1"},
+		{name: "empty mountpoint, no error", resp: `{"Mountpoint":""}`, want: ""},
+		{name: "plugin error", resp: `{"Err":"volume not found"}`, wantErr: true},
+		{name: "invalid json", resp: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mountpointFromResponse([]byte(tt.resp))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("mountpointFromResponse(%q) = nil error, want error", tt.resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mountpointFromResponse(%q) unexpected error: %v", tt.resp, err)
+			}
+			if got != tt.want {
+				t.Errorf("mountpointFromResponse(%q) = %q, want %q", tt.resp, got, tt.want)
+			}
+		})
+	}
+}
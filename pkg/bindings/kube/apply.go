@@ -0,0 +1,163 @@
+package kube
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ApplyReport describes the outcome of applying a single object from the
+// manifest passed to Apply/ApplyWithBody.
+type ApplyReport struct {
+	// Kind is the Kubernetes kind of the applied object (Pod, Deployment, ...).
+	Kind string
+	// Name is the name of the applied object.
+	Name string
+	// Namespace the object was applied into.
+	Namespace string
+}
+
+// Apply converts the Kubernetes YAML produced by `podman kube generate` (or
+// any compatible manifest) read from path and applies it against a real
+// Kubernetes cluster, the way `kubectl apply -f` would. It lets a Podman-only
+// workflow go from `podman kube generate` to a running cluster without
+// requiring kubectl to be installed.
+func Apply(ctx context.Context, path string, options *ApplyOptions) ([]ApplyReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ApplyWithBody(ctx, f, options)
+}
+
+// ApplyWithBody is Apply but takes the manifest as an io.Reader.
+func ApplyWithBody(ctx context.Context, body io.Reader, options *ApplyOptions) ([]ApplyReport, error) {
+	if options == nil {
+		options = new(ApplyOptions)
+	}
+
+	restConfig, err := buildRESTConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []ApplyReport
+	decoder := yaml.NewYAMLOrJSONDecoder(body, 4096)
+	for {
+		var obj unstructured.Unstructured
+		if err := decoder.Decode(&obj); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return reports, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		report, err := applyObject(ctx, &obj, mapper, dynamicClient, options)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, nil
+}
+
+func applyObject(ctx context.Context, obj *unstructured.Unstructured, mapper meta.RESTMapper, client dynamic.Interface, options *ApplyOptions) (*ApplyReport, error) {
+	gvk := obj.GroupVersionKind()
+	restMapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := obj.GetNamespace()
+	if options.Namespace != nil {
+		namespace = options.GetNamespace()
+		obj.SetNamespace(namespace)
+	}
+
+	var resource dynamic.ResourceInterface
+	if restMapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = client.Resource(restMapping.Resource).Namespace(namespace)
+	} else {
+		resource = client.Resource(restMapping.Resource)
+	}
+
+	applyOpts := metav1.ApplyOptions{FieldManager: "podman", Force: true}
+	if options.DryRun != nil && options.GetDryRun() {
+		applyOpts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	data, err := runtimeToYAML(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := resource.Patch(ctx, obj.GetName(), "application/apply-patch+yaml", data, applyOpts.ToPatchOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApplyReport{
+		Kind:      applied.GetKind(),
+		Name:      applied.GetName(),
+		Namespace: applied.GetNamespace(),
+	}, nil
+}
+
+func runtimeToYAML(obj *unstructured.Unstructured) ([]byte, error) {
+	printer := &printers.YAMLPrinter{}
+	var buf bytes.Buffer
+	if err := printer.PrintObj(obj, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// buildRESTConfig resolves a REST config from the kubeconfig path and context
+// named in options, falling back to the standard KUBECONFIG/loading rules.
+func buildRESTConfig(options *ApplyOptions) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if options.Kubeconfig != nil {
+		loadingRules.ExplicitPath = options.GetKubeconfig()
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	if options.Context != nil {
+		overrides.CurrentContext = options.GetContext()
+	}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,155 @@
+package kube
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/containers/image/v5/types"
+	"github.com/containers/podman/v4/pkg/auth"
+	"github.com/containers/podman/v4/pkg/bindings"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+)
+
+// PlayEventType identifies the kind of progress event emitted by PlayStream.
+type PlayEventType string
+
+const (
+	// PlayEventPull is emitted for image pull progress, one event per layer update.
+	PlayEventPull PlayEventType = "pull"
+	// PlayEventPodCreated is emitted once a pod has been created.
+	PlayEventPodCreated PlayEventType = "pod-created"
+	// PlayEventContainerStarted is emitted once a container has started.
+	PlayEventContainerStarted PlayEventType = "container-started"
+	// PlayEventError is emitted when a workload fails to start.
+	PlayEventError PlayEventType = "error"
+	// PlayEventDone is the final event on the stream; Report is populated with
+	// the same contents PlayWithBody would have returned in non-streaming mode.
+	PlayEventDone PlayEventType = "done"
+)
+
+// PlayEvent is a single entry in the newline-delimited JSON stream produced by
+// PlayStream.
+type PlayEvent struct {
+	Type PlayEventType `json:"type"`
+	// ID is the pod, container, or image ID the event refers to, when applicable.
+	ID string `json:"id,omitempty"`
+	// Name is a human-readable identifier (pod name, container name, image reference).
+	Name string `json:"name,omitempty"`
+	// Error is set when Type is PlayEventError.
+	Error string `json:"error,omitempty"`
+	// Report is only set on the final PlayEventDone event.
+	Report *entities.KubePlayReport `json:"report,omitempty"`
+}
+
+// PlayStream behaves like PlayWithBody, but requests a newline-delimited JSON
+// event stream from the server instead of waiting for a single response. The
+// returned channel is closed once the final event (PlayEventDone) has been
+// received or the request fails.
+func PlayStream(ctx context.Context, path string, options *PlayOptions) (<-chan PlayEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return PlayStreamWithBody(ctx, f, options, f.Close)
+}
+
+// PlayStreamWithBody is PlayStream but takes the kube YAML as an io.Reader
+// rather than a file path. closeBody, if non-nil, is called once the event
+// channel has been fully drained.
+func PlayStreamWithBody(ctx context.Context, body io.Reader, options *PlayOptions, closeBody func() error) (<-chan PlayEvent, error) {
+	// Ownership of closeBody transfers to the streaming goroutine only once
+	// it's actually started; any error return before that point has to close
+	// it here instead, or callers like PlayStream that hand us a just-opened
+	// file would leak it.
+	streaming := false
+	if closeBody != nil {
+		defer func() {
+			if !streaming {
+				closeBody()
+			}
+		}()
+	}
+
+	if options == nil {
+		options = new(PlayOptions)
+	}
+	options.WithStream(true)
+
+	conn, err := bindings.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := options.ToParams()
+	if err != nil {
+		return nil, err
+	}
+	params.Set("stream", strconv.FormatBool(true))
+	if options.SkipTLSVerify != nil {
+		params.Set("tlsVerify", strconv.FormatBool(options.GetSkipTLSVerify()))
+	}
+	if options.Start != nil {
+		params.Set("start", strconv.FormatBool(options.GetStart()))
+	}
+
+	header, err := auth.MakeXRegistryAuthHeader(&types.SystemContext{AuthFilePath: options.GetAuthfile()}, options.GetUsername(), options.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := conn.DoRequest(ctx, body, http.MethodPost, "/kube/play", params, header)
+	if err != nil {
+		return nil, err
+	}
+
+	streaming = true
+	events := make(chan PlayEvent)
+	go func() {
+		defer close(events)
+		defer response.Body.Close()
+		if closeBody != nil {
+			defer closeBody()
+		}
+
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			var event PlayEvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				events <- PlayEvent{Type: PlayEventError, Error: fmt.Sprintf("decoding kube play event: %v", err)}
+				return
+			}
+			events <- event
+		}
+		if err := scanner.Err(); err != nil {
+			events <- PlayEvent{Type: PlayEventError, Error: err.Error()}
+		}
+	}()
+
+	return events, nil
+}
+
+// PlayStreamReport drains a PlayEvent channel and collapses it into the
+// equivalent KubePlayReport, for callers migrating from Play/PlayWithBody
+// that do not care about incremental progress.
+func PlayStreamReport(events <-chan PlayEvent) (*entities.KubePlayReport, error) {
+	var report *entities.KubePlayReport
+	for event := range events {
+		switch event.Type {
+		case PlayEventError:
+			return nil, fmt.Errorf("kube play: %s", event.Error)
+		case PlayEventDone:
+			report = event.Report
+		}
+	}
+	if report == nil {
+		return nil, fmt.Errorf("kube play: stream closed before a final report was received")
+	}
+	return report, nil
+}
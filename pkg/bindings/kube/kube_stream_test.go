@@ -0,0 +1,46 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/containers/podman/v4/pkg/domain/entities"
+)
+
+func TestPlayStreamReport(t *testing.T) {
+	t.Run("done event yields its report", func(t *testing.T) {
+		report := &entities.KubePlayReport{}
+		events := make(chan PlayEvent, 3)
+		events <- PlayEvent{Type: PlayEventPull, Name: "example.com/image:latest"}
+		events <- PlayEvent{Type: PlayEventPodCreated, ID: "pod1"}
+		events <- PlayEvent{Type: PlayEventDone, Report: report}
+		close(events)
+
+		got, err := PlayStreamReport(events)
+		if err != nil {
+			t.Fatalf("PlayStreamReport() unexpected error: %v", err)
+		}
+		if got != report {
+			t.Errorf("PlayStreamReport() = %v, want the report from the done event", got)
+		}
+	})
+
+	t.Run("error event surfaces as an error", func(t *testing.T) {
+		events := make(chan PlayEvent, 1)
+		events <- PlayEvent{Type: PlayEventError, Error: "pod creation failed"}
+		close(events)
+
+		if _, err := PlayStreamReport(events); err == nil {
+			t.Fatal("PlayStreamReport() = nil error, want error")
+		}
+	})
+
+	t.Run("stream closed without a done event", func(t *testing.T) {
+		events := make(chan PlayEvent, 1)
+		events <- PlayEvent{Type: PlayEventContainerStarted, ID: "ctr1"}
+		close(events)
+
+		if _, err := PlayStreamReport(events); err == nil {
+			t.Fatal("PlayStreamReport() = nil error, want error")
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package kube
+
+//go:generate go run ../generator/generator.go PlayOptions
+// PlayOptions are optional options for replaying kube YAML files
+type PlayOptions struct {
+	// Authfile - path to an authentication file.
+	Authfile *string
+	// Username for authenticating against the registry.
+	Username *string
+	// Password for authenticating against the registry.
+	Password *string
+	// Start - don't start the pod if defined in the YAML file
+	Start *bool
+	// SkipTLSVerify - Require HTTPS and verify signatures when contacting registries.
+	SkipTLSVerify *bool
+	// Stream, if true, requests a newline-delimited JSON event stream instead of
+	// a single KubePlayReport. Use PlayStream to consume it.
+	Stream *bool
+}
+
+//go:generate go run ../generator/generator.go ApplyOptions
+// ApplyOptions are optional options for applying kube YAML to a Kubernetes cluster
+type ApplyOptions struct {
+	// Kubeconfig - path to a kubeconfig file. If unset, the standard
+	// KUBECONFIG/`~/.kube/config` resolution rules apply.
+	Kubeconfig *string
+	// Context - name of the context within the kubeconfig to use. If unset,
+	// the kubeconfig's current-context is used.
+	Context *string
+	// Namespace - namespace to apply the manifests in, overriding whatever
+	// namespace is set on each object.
+	Namespace *string
+	// DryRun - validate and send the request to the Kubernetes API server as a
+	// dry run, without persisting any changes.
+	DryRun *bool
+}
@@ -0,0 +1,154 @@
+//go:build amd64 || arm64
+// +build amd64 arm64
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/transports/alltransports"
+)
+
+// DistributionSource resolves where a machine rootfs should be fetched from.
+// NewFedoraDownloader defaults to githubReleaseSource, but WithDistributionSource
+// lets callers in air-gapped or mirror-only environments point at an internal
+// rootfs without patching the binary.
+type DistributionSource interface {
+	// Resolve returns the URL to fetch the rootfs from, its size in bytes,
+	// and a sha256 checksum if one is known (empty string if not).
+	Resolve() (rootfsURL *url.URL, size int64, checksum string, err error)
+}
+
+// githubReleaseSource resolves the rootfs from the containers/podman-wsl-fedora
+// GitHub release, downloading a companion .sha256 file when present. This is
+// the historical, and default, behavior of NewFedoraDownloader.
+type githubReleaseSource struct {
+	releaseURL string
+}
+
+// NewGitHubReleaseSource resolves the rootfs from a GitHub release asset URL,
+// such as githubLatestReleaseURL.
+func NewGitHubReleaseSource(releaseURL string) DistributionSource {
+	return githubReleaseSource{releaseURL: releaseURL}
+}
+
+func (s githubReleaseSource) Resolve() (*url.URL, int64, string, error) {
+	downloadURL, size, _, err := getFedoraDownload(s.releaseURL)
+	if err != nil {
+		return nil, -1, "", err
+	}
+
+	checksum, err := getFedoraChecksum(s.releaseURL + ".sha256")
+	if err != nil {
+		checksum = ""
+	}
+
+	return downloadURL, size, checksum, nil
+}
+
+// httpSource resolves the rootfs from a plain HTTP(S) URL supplied by the
+// user, e.g. an internal mirror.
+type httpSource struct {
+	url string
+}
+
+// NewHTTPSource resolves the rootfs from an arbitrary HTTP(S) URL.
+func NewHTTPSource(rawURL string) DistributionSource {
+	return httpSource{url: rawURL}
+}
+
+func (s httpSource) Resolve() (*url.URL, int64, string, error) {
+	downloadURL, size, _, err := getFedoraDownload(s.url)
+	if err != nil {
+		return nil, -1, "", err
+	}
+	return downloadURL, size, "", nil
+}
+
+// ociSource resolves the rootfs from a single-layer OCI registry artifact,
+// e.g. "docker://registry.example.com/podman-wsl-fedora:latest", pulling the
+// blob via containers/image rather than a plain HTTP GET.
+type ociSource struct {
+	reference string
+}
+
+// NewOCISource resolves the rootfs from an OCI registry artifact reference.
+func NewOCISource(reference string) DistributionSource {
+	return ociSource{reference: reference}
+}
+
+func (s ociSource) Resolve() (*url.URL, int64, string, error) {
+	srcRef, err := alltransports.ParseImageName(s.reference)
+	if err != nil {
+		return nil, -1, "", fmt.Errorf("parsing OCI rootfs reference %q: %w", s.reference, err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "podman-machine-oci-rootfs")
+	if err != nil {
+		return nil, -1, "", err
+	}
+	localPath := filepath.Join(tmpDir, "rootfs-oci.tar.xz")
+
+	destRef, err := layout.ParseReference(localPath)
+	if err != nil {
+		return nil, -1, "", err
+	}
+
+	policy, err := signature.DefaultPolicy(nil)
+	if err != nil {
+		return nil, -1, "", err
+	}
+	policyCtx, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return nil, -1, "", err
+	}
+	defer policyCtx.Destroy()
+
+	if _, err := copy.Image(context.Background(), policyCtx, destRef, srcRef, nil); err != nil {
+		return nil, -1, "", fmt.Errorf("pulling OCI rootfs artifact %q: %w", s.reference, err)
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, -1, "", err
+	}
+
+	localURL := &url.URL{Scheme: "file", Path: localPath}
+	checksum, err := sha256SumFile(localPath)
+	if err != nil {
+		checksum = ""
+	}
+	return localURL, info.Size(), checksum, nil
+}
+
+// localFileSource resolves the rootfs from a path already on disk, e.g. one
+// staged by configuration management in an air-gapped environment.
+type localFileSource struct {
+	path string
+}
+
+// NewLocalFileSource resolves the rootfs from a local file path.
+func NewLocalFileSource(path string) DistributionSource {
+	return localFileSource{path: path}
+}
+
+func (s localFileSource) Resolve() (*url.URL, int64, string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, -1, "", err
+	}
+
+	checksum, err := sha256SumFile(s.path)
+	if err != nil {
+		return nil, -1, "", err
+	}
+
+	return &url.URL{Scheme: "file", Path: s.path}, info.Size(), checksum, nil
+}
@@ -4,13 +4,17 @@
 package machine
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -20,13 +24,53 @@ const (
 
 type FedoraDownload struct {
 	Download
+	// Sha256Sum is the expected checksum of the fully-downloaded rootfs, as
+	// reported by the DistributionSource: read from the companion .sha256
+	// file for a GitHub release, computed from the file for local and OCI
+	// sources. Empty if none is known.
+	Sha256Sum string
+	// resumable records whether the server advertised Accept-Ranges: bytes
+	// for the rootfs, so a partial LocalPath can be resumed instead of
+	// restarted.
+	resumable bool
 }
 
-func NewFedoraDownloader(vmType, vmName, releaseStream string) (DistributionDownload, error) {
-	downloadURL, size, err := getFedoraDownload(githubLatestReleaseURL)
+// FedoraDownloaderOption customizes NewFedoraDownloader.
+type FedoraDownloaderOption func(*fedoraDownloaderConfig)
+
+type fedoraDownloaderConfig struct {
+	source DistributionSource
+}
+
+// WithDistributionSource overrides where the rootfs is fetched from. Operators
+// in air-gapped or mirror-only environments can use this to point
+// `podman machine init` at an internal rootfs (HTTP mirror, OCI registry
+// artifact, or local file) without patching the binary. Defaults to
+// NewGitHubReleaseSource(githubLatestReleaseURL) when not provided.
+func WithDistributionSource(source DistributionSource) FedoraDownloaderOption {
+	return func(cfg *fedoraDownloaderConfig) {
+		cfg.source = source
+	}
+}
+
+func NewFedoraDownloader(vmType, vmName, releaseStream string, options ...FedoraDownloaderOption) (DistributionDownload, error) {
+	cfg := fedoraDownloaderConfig{
+		source: NewGitHubReleaseSource(githubLatestReleaseURL),
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+
+	downloadURL, size, sha256sum, err := cfg.source.Resolve()
 	if err != nil {
 		return nil, err
 	}
+	resumable := downloadURL.Scheme == "http" || downloadURL.Scheme == "https"
+	if resumable {
+		if _, _, ranged, err := getFedoraDownload(downloadURL.String()); err == nil {
+			resumable = ranged
+		}
+	}
 
 	cacheDir, err := GetCacheDir(vmType)
 	if err != nil {
@@ -47,6 +91,8 @@ func NewFedoraDownloader(vmType, vmName, releaseStream string) (DistributionDown
 			VMName:    vmName,
 			Size:      size,
 		},
+		Sha256Sum: sha256sum,
+		resumable: resumable,
 	}
 	dataDir, err := GetDataDir(vmType)
 	if err != nil {
@@ -60,6 +106,11 @@ func (f FedoraDownload) Get() *Download {
 	return &f.Download
 }
 
+// HasUsableCache reports whether the file at LocalPath can be used without
+// re-downloading. A cache is usable only if its size matches the remote
+// Content-Length and, when a checksum was published for the release, its
+// sha256 digest matches too - a partially-downloaded or corrupted file with
+// the right size but wrong contents is rejected.
 func (f FedoraDownload) HasUsableCache() (bool, error) {
 	info, err := os.Stat(f.LocalPath)
 	if err != nil {
@@ -68,7 +119,18 @@ func (f FedoraDownload) HasUsableCache() (bool, error) {
 		}
 		return false, err
 	}
-	return info.Size() == f.Size, nil
+	if info.Size() != f.Size {
+		return false, nil
+	}
+	if f.Sha256Sum == "" {
+		return true, nil
+	}
+
+	sum, err := sha256SumFile(f.LocalPath)
+	if err != nil {
+		return false, err
+	}
+	return sum == f.Sha256Sum, nil
 }
 
 func (f FedoraDownload) CleanCache() error {
@@ -77,21 +139,203 @@ func (f FedoraDownload) CleanCache() error {
 	return removeImageAfterExpire(f.CacheDir, expire)
 }
 
-func getFedoraDownload(releaseURL string) (*url.URL, int64, error) {
+// DownloadRootfs fetches the rootfs to f.LocalPath, resuming a previous
+// partial download when one exists and the server supports range requests,
+// and verifying the result against f.Sha256Sum when one was published.
+// progress, if non-nil, is invoked after each chunk is written with the
+// number of bytes downloaded so far and the total expected size.
+//
+// f.URL may also be a file:// URL, as produced by NewOCISource and
+// NewLocalFileSource - in that case the rootfs is simply copied from disk,
+// since there is no HTTP server to range-request against.
+func (f FedoraDownload) DownloadRootfs(progress func(downloaded, total int64)) error {
+	if f.URL.Scheme == "file" {
+		return f.copyLocalRootfs(progress)
+	}
+
+	var (
+		offset int64
+		flags  = os.O_CREATE | os.O_WRONLY
+	)
+
+	if info, err := os.Stat(f.LocalPath); err == nil && f.resumable {
+		offset = info.Size()
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+
+	req, err := http.NewRequest(http.MethodGet, f.URL.String(), nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", f.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if offset > 0 && resp.StatusCode == http.StatusOK {
+		// Server ignored our Range request; restart from scratch.
+		offset = 0
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	} else if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("downloading %s: unexpected status %s", f.URL, resp.Status)
+	}
+
+	out, err := os.OpenFile(f.LocalPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	downloaded := offset
+	buf := make([]byte, 1024*1024)
+	for {
+		n, rerr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := out.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			downloaded += int64(n)
+			if progress != nil {
+				progress(downloaded, f.Size)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+
+	if f.Sha256Sum != "" {
+		sum, err := sha256SumFile(f.LocalPath)
+		if err != nil {
+			return err
+		}
+		if sum != f.Sha256Sum {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", f.LocalPath, f.Sha256Sum, sum)
+		}
+	}
+
+	return nil
+}
+
+// copyLocalRootfs handles the file:// case of DownloadRootfs: the rootfs is
+// already on disk (staged by NewLocalFileSource, or pulled to a temp file by
+// NewOCISource), so it's copied to f.LocalPath rather than fetched over HTTP.
+// Range-resuming a partial copy isn't worth the complexity here since local
+// copies are fast; a partial f.LocalPath is simply overwritten.
+func (f FedoraDownload) copyLocalRootfs(progress func(downloaded, total int64)) error {
+	in, err := os.Open(f.URL.Path)
+	if err != nil {
+		return fmt.Errorf("opening local rootfs %s: %w", f.URL.Path, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(f.LocalPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var downloaded int64
+	buf := make([]byte, 1024*1024)
+	for {
+		n, rerr := in.Read(buf)
+		if n > 0 {
+			if _, werr := out.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			downloaded += int64(n)
+			if progress != nil {
+				progress(downloaded, f.Size)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+
+	if f.Sha256Sum != "" {
+		sum, err := sha256SumFile(f.LocalPath)
+		if err != nil {
+			return err
+		}
+		if sum != f.Sha256Sum {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", f.LocalPath, f.Sha256Sum, sum)
+		}
+	}
+
+	return nil
+}
+
+func getFedoraDownload(releaseURL string) (*url.URL, int64, bool, error) {
 	downloadURL, err := url.Parse(releaseURL)
 	if err != nil {
-		return nil, -1, fmt.Errorf("invalid URL generated from discovered Fedora file: %s: %w", releaseURL, err)
+		return nil, -1, false, fmt.Errorf("invalid URL generated from discovered Fedora file: %s: %w", releaseURL, err)
 	}
 
 	resp, err := http.Head(releaseURL)
 	if err != nil {
-		return nil, -1, fmt.Errorf("head request failed: %s: %w", releaseURL, err)
+		return nil, -1, false, fmt.Errorf("head request failed: %s: %w", releaseURL, err)
 	}
 	_ = resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, -1, fmt.Errorf("head request failed: %s: %w", releaseURL, err)
+		return nil, -1, false, fmt.Errorf("head request failed: %s: %w", releaseURL, err)
+	}
+
+	resumable := strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes")
+
+	return downloadURL, resp.ContentLength, resumable, nil
+}
+
+// getFedoraChecksum fetches the companion .sha256 file published alongside a
+// release and returns the digest it contains.
+func getFedoraChecksum(checksumURL string) (string, error) {
+	resp, err := http.Get(checksumURL)
+	if err != nil {
+		return "", fmt.Errorf("fetching checksum %s: %w", checksumURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching checksum %s: unexpected status %s", checksumURL, resp.Status)
 	}
 
-	return downloadURL, resp.ContentLength, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// Checksum files are conventionally "<sha256>  <filename>" or a bare sum.
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("checksum file %s is empty", checksumURL)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+func sha256SumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
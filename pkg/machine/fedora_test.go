@@ -0,0 +1,104 @@
+//go:build amd64 || arm64
+// +build amd64 arm64
+
+package machine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetFedoraChecksum(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		want    string
+		wantErr bool
+	}{
+		{name: "bare sum", body: "abc123\n", status: http.StatusOK, want: "abc123"},
+		{name: "sum and filename", body: "ABC123  rootfs.tar.xz\n", status: http.StatusOK, want: "abc123"},
+		{name: "not found", body: "", status: http.StatusNotFound, wantErr: true},
+		{name: "empty body", body: "", status: http.StatusOK, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			got, err := getFedoraChecksum(srv.URL)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("getFedoraChecksum() = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getFedoraChecksum() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getFedoraChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFedoraDownloadHasUsableCache(t *testing.T) {
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "rootfs.tar.xz")
+	content := []byte("fake rootfs contents")
+	if err := os.WriteFile(localPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	validSum, err := sha256SumFile(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		size      int64
+		sha256Sum string
+		want      bool
+	}{
+		{name: "no checksum, size matches", size: int64(len(content)), want: true},
+		{name: "size mismatch", size: int64(len(content)) + 1, want: false},
+		{name: "checksum matches", size: int64(len(content)), sha256Sum: validSum, want: true},
+		{name: "checksum mismatch", size: int64(len(content)), sha256Sum: "deadbeef", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := FedoraDownload{
+				Download:  Download{LocalPath: localPath, Size: tt.size},
+				Sha256Sum: tt.sha256Sum,
+			}
+			got, err := f.HasUsableCache()
+			if err != nil {
+				t.Fatalf("HasUsableCache() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasUsableCache() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		f := FedoraDownload{Download: Download{LocalPath: filepath.Join(dir, "missing"), Size: 1}}
+		got, err := f.HasUsableCache()
+		if err != nil {
+			t.Fatalf("HasUsableCache() unexpected error: %v", err)
+		}
+		if got {
+			t.Errorf("HasUsableCache() = true for missing file, want false")
+		}
+	})
+}